@@ -1,2 +1,19 @@
-rm -rf eth-* && rm -rf candy-* && xgo --targets=windows/amd64 --ldflags="-s -w -H=windowsgui" --pkg github.com/naiba/eth-tools/cmd/$1 -v . &&
-    xgo --targets=darwin/amd64 --ldflags="-s -w" --pkg github.com/naiba/eth-tools/cmd/$1 -v .
+rm -rf eth-* && rm -rf candy-* && rm -rf faucet-* && rm -rf stand-in-*
+case $1 in
+*-cli | faucet-server | stand-in-signer)
+    # 不依赖界面库，直接交叉编译
+    for target in linux/amd64 windows/amd64 darwin/amd64; do
+        ext=""
+        [ "${target%/*}" = windows ] && ext=".exe"
+        CGO_ENABLED=0 GOOS=${target%/*} GOARCH=${target#*/} go build -ldflags="-s -w" -o "$1-${target%/*}-${target#*/}$ext" ./cmd/$1 || exit 1
+    done
+    ;;
+*)
+    xgo --targets=windows/amd64 --ldflags="-s -w -H=windowsgui" --pkg github.com/naiba/eth-tools/cmd/$1 -v . &&
+        xgo --targets=darwin/amd64 --ldflags="-s -w" --pkg github.com/naiba/eth-tools/cmd/$1 -v . || exit 1
+    # Linux 界面版本依赖 GTK3，只在装有 libgtk-3-dev 的 Linux 上本机编译
+    if [ "$(uname)" = Linux ]; then
+        go build -ldflags="-s -w" -o "$1-linux-amd64" ./cmd/$1
+    fi
+    ;;
+esac
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/naiba/eth-tools/internal/keys"
+)
+
+var exportKeystore = flag.String("export-keystore", "", "把 -key-source、-key-file 或 -key-env 指定的私钥加密导出为 keystore 文件（可以是目录），环境变量中没有私钥时生成新私钥")
+
+// runExport 导出 keystore 文件，返回进程退出码
+func runExport() int {
+	if err := exportKey(*exportKeystore); err != nil {
+		cliLog(err.Error())
+		return 1
+	}
+	return 0
+}
+
+func exportKey(path string) error {
+	job, err := readJob()
+	if err != nil {
+		return err
+	}
+	spec, err := job.keySpec()
+	if err != nil {
+		return err
+	}
+	var pk *ecdsa.PrivateKey
+	if spec.Kind == keys.KindEnv && strings.TrimSpace(os.Getenv(spec.Location)) == "" {
+		cliLog(fmt.Sprintf("环境变量 %s 中没有私钥，生成新私钥", spec.Location))
+		if pk, err = crypto.GenerateKey(); err != nil {
+			return fmt.Errorf("生成私钥失败：%s", err)
+		}
+	} else {
+		secret, err := job.secret(spec)
+		if err != nil {
+			return err
+		}
+		if pk, err = keys.PrivateKey(spec, secret); err != nil {
+			return err
+		}
+	}
+	passphrase, err := prompt.Stdin.PromptPassword("请设置 keystore 的密码：")
+	if err != nil {
+		return fmt.Errorf("读取密码失败：%s", err)
+	}
+	confirm, err := prompt.Stdin.PromptPassword("请再次输入密码：")
+	if err != nil {
+		return fmt.Errorf("读取密码失败：%s", err)
+	}
+	if passphrase != confirm {
+		return errors.New("两次输入的密码不一致")
+	}
+	path, err = keys.ExportKeystore(pk, passphrase, path)
+	if err != nil {
+		return err
+	}
+	cliLog(fmt.Sprintf("已导出 %s 到 %s", crypto.PubkeyToAddress(pk.PublicKey).Hex(), path))
+	return nil
+}
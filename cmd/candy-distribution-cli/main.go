@@ -0,0 +1,294 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/naiba/eth-tools/internal/candy"
+	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/keys"
+	"github.com/naiba/eth-tools/internal/netconf"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// shared 与界面版本共用的参数
+var shared = candy.RegisterFlags(flag.CommandLine)
+
+var (
+	jobFile     = flag.String("job", "", "任务文件（JSON），命令行参数会覆盖其中的同名字段")
+	keyEnv      = flag.String("key-env", "CANDY_PRIVATE_KEY", "读取钱包私钥的环境变量")
+	keyFile     = flag.String("key-file", "", "钱包私钥文件，优先于 -key-env")
+	keySource   = flag.String("key-source", "", "密钥来源：keystore、vault、mnemonic、env、file 或 remote，设置后代替 -key-env 与 -key-file")
+	keyLoc      = flag.String("key-location", "", "密钥位置：keystore 或私钥文件路径、环境变量名、保管库文件（留空为默认位置）、远程签名服务地址（http、ws 或 IPC 文件路径）、助记词派生路径（留空为 m/44'/60'/0'/0/n）")
+	keyAccount  = flag.String("key-account", "", "保管库中的密钥名称，远程签名服务中的账户地址，或助记词派生的账户序号")
+	passEnv     = flag.String("passphrase-env", "CANDY_PASSPHRASE", "读取 keystore 或保管库密码的环境变量，为空时在终端输入；助记词来源时作为可选的 BIP-39 密码")
+	mnemonicEnv = flag.String("mnemonic-env", "CANDY_MNEMONIC", "读取助记词的环境变量，为空时在终端输入")
+	tokenFlag   = flag.String("token", "", "代币地址")
+	amountFlag  = flag.String("amount", "", "每个钱包分发的代币数量，可以有小数，如 1.5，钱包文件中已指定数量的以文件为准")
+	walletFlag  = flag.String("wallets", "", "目标钱包文件")
+	rpcFlag     = flag.String("rpc", "", "节点地址，设置后代替网络配置中的节点地址")
+	jsonOutput  = flag.Bool("json", false, "以 JSON Lines 格式输出日志")
+	dryRunFlag  = flag.Bool("dry-run", false, "只预演分发，检查余额并估算费用，不广播交易")
+)
+
+var (
+	feeMode   = flag.String("fee", "", "交易类型：auto、legacy 或 1559，留空使用网络配置中的交易类型")
+	maxFeeCap = flag.String("max-fee", "", "最高 Gas 费用（Gwei），Legacy 交易限制 GasPrice，EIP-1559 交易限制 GasFeeCap，留空不限制")
+	maxTipCap = flag.String("max-tip", "", "EIP-1559 交易的最高小费（Gwei），留空不限制")
+)
+
+var (
+	batchMode    = flag.Bool("batch", false, "通过 Disperse 批量转账合约分发")
+	disperseAddr = flag.String("disperse", "", "复用已部署的 Disperse 合约地址，留空则部署新合约")
+	merkleMode   = flag.Bool("merkle", false, "生成 Merkle 领取证明，由用户自行领取，不直接转账")
+	merkleOut    = flag.String("merkle-out", "", "领取证明文件，默认保存在钱包文件旁")
+	merkleDeploy = flag.Bool("merkle-deploy", false, "生成证明后部署领取合约并转入代币")
+)
+
+// cliJob 命令行模式的分发任务
+type cliJob struct {
+	KeyEnv      string       `json:"key_env"`
+	KeyFile     string       `json:"key_file"`
+	Key         keys.Spec    `json:"key"`
+	PassEnv     string       `json:"passphrase_env"`
+	MnemonicEnv string       `json:"mnemonic_env"`
+	Token       string       `json:"token"`
+	Amount      units.Amount `json:"amount"` // 数字或字符串，如 100、"1.5"
+	Wallets     string       `json:"wallets"`
+	Network     string       `json:"network"`
+	RPC         string       `json:"rpc"`
+	Fee         string       `json:"fee"`
+	MaxFee      string       `json:"max_fee"`
+	MaxTip      string       `json:"max_tip"`
+}
+
+// readJob 读取任务文件并用显式传入的参数覆盖，不做校验
+func readJob() (*cliJob, error) {
+	job := &cliJob{
+		KeyEnv:      *keyEnv,
+		PassEnv:     *passEnv,
+		MnemonicEnv: *mnemonicEnv,
+		Network:     shared.Network,
+		RPC:         *rpcFlag,
+		Fee:         *feeMode,
+	}
+	if *jobFile != "" {
+		data, err := ioutil.ReadFile(*jobFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取任务文件失败：%s", err)
+		}
+		if err := json.Unmarshal(data, job); err != nil {
+			return nil, fmt.Errorf("解析任务文件失败：%s", err)
+		}
+	}
+	// 显式传入的参数覆盖任务文件
+	var amountErr error
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "key-env":
+			job.KeyEnv = *keyEnv
+		case "key-file":
+			job.KeyFile = *keyFile
+		case "key-source":
+			job.Key.Kind = keys.Kind(*keySource)
+		case "key-location":
+			job.Key.Location = *keyLoc
+		case "key-account":
+			job.Key.Account = *keyAccount
+		case "passphrase-env":
+			job.PassEnv = *passEnv
+		case "mnemonic-env":
+			job.MnemonicEnv = *mnemonicEnv
+		case "token":
+			job.Token = *tokenFlag
+		case "amount":
+			job.Amount, amountErr = units.ParseAmount(*amountFlag)
+		case "wallets":
+			job.Wallets = *walletFlag
+		case "network":
+			job.Network = shared.Network
+		case "rpc":
+			job.RPC = *rpcFlag
+		case "fee":
+			job.Fee = *feeMode
+		case "max-fee":
+			job.MaxFee = *maxFeeCap
+		case "max-tip":
+			job.MaxTip = *maxTipCap
+		}
+	})
+	if amountErr != nil {
+		return nil, fmt.Errorf("分发数量有误：%s", amountErr)
+	}
+	return job, nil
+}
+
+func loadJob() (*cliJob, error) {
+	job, err := readJob()
+	if err != nil {
+		return nil, err
+	}
+	if job.Token == "" {
+		return nil, errors.New("未指定代币地址")
+	}
+	if job.Wallets == "" {
+		return nil, errors.New("未指定目标钱包文件")
+	}
+	return job, nil
+}
+
+// network 按任务中的网络名称读取网络配置，指定了节点地址时代替配置中的地址
+func (job *cliJob) network() (netconf.Profile, error) {
+	cfg, err := netconf.Load(shared.Networks)
+	if err != nil {
+		return netconf.Profile{}, err
+	}
+	p, ok := cfg.Find(job.Network)
+	if !ok {
+		return p, fmt.Errorf("网络配置中没有 %s，可选：%s", job.Network, strings.Join(cfg.Names(), "、"))
+	}
+	if job.RPC != "" {
+		p.RPC = []string{job.RPC}
+	}
+	return p, nil
+}
+
+// keySpec 任务中的密钥来源，没有指定时兼容旧的 -key-file 与 -key-env
+func (job *cliJob) keySpec() (keys.Spec, error) {
+	spec := job.Key
+	switch {
+	case spec.Kind != "":
+		kind, err := keys.ParseKind(string(spec.Kind))
+		if err != nil {
+			return spec, err
+		}
+		spec.Kind = kind
+	case job.KeyFile != "":
+		spec = keys.Spec{Kind: keys.KindFile, Location: job.KeyFile}
+	default:
+		spec = keys.Spec{Kind: keys.KindEnv, Location: job.KeyEnv}
+	}
+	return spec, nil
+}
+
+// secret 读取密钥来源的密码与助记词，先读环境变量，没有再在终端输入
+func (job *cliJob) secret(spec keys.Spec) (keys.Secret, error) {
+	var secret keys.Secret
+	if job.PassEnv != "" {
+		secret.Passphrase = os.Getenv(job.PassEnv)
+	}
+	if spec.Kind == keys.KindMnemonic {
+		// BIP-39 密码是可选的，只从环境变量读取
+		if job.MnemonicEnv != "" {
+			secret.Mnemonic = os.Getenv(job.MnemonicEnv)
+		}
+		if strings.TrimSpace(secret.Mnemonic) == "" {
+			mnemonic, err := prompt.Stdin.PromptPassword("请输入助记词：")
+			if err != nil {
+				return secret, fmt.Errorf("读取助记词失败：%s", err)
+			}
+			secret.Mnemonic = mnemonic
+		}
+		return secret, nil
+	}
+	if !spec.NeedPassphrase() {
+		return keys.Secret{}, nil
+	}
+	if secret.Passphrase == "" {
+		passphrase, err := prompt.Stdin.PromptPassword(fmt.Sprintf("请输入 %s 的密码：", spec))
+		if err != nil {
+			return secret, fmt.Errorf("读取密码失败：%s", err)
+		}
+		secret.Passphrase = passphrase
+	}
+	return secret, nil
+}
+
+// signer 按任务中的密钥来源打开签名账户
+func (job *cliJob) signer() (ethutil.Signer, error) {
+	spec, err := job.keySpec()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := job.secret(spec)
+	if err != nil {
+		return nil, err
+	}
+	return keys.Open(spec, secret)
+}
+
+func cliLog(msg string) {
+	if *jsonOutput {
+		json.NewEncoder(os.Stdout).Encode(map[string]string{
+			"time": time.Now().Format(time.RFC3339),
+			"msg":  msg,
+		})
+		return
+	}
+	fmt.Println(time.Now().Format("15:04:05") + "：" + msg)
+}
+
+// runCLI 执行一次分发，返回进程退出码：参数有误为 2，分发失败为 1
+func runCLI() int {
+	job, err := loadJob()
+	if err != nil {
+		cliLog(err.Error())
+		return 2
+	}
+	signer, err := job.signer()
+	if err != nil {
+		cliLog(err.Error())
+		return 2
+	}
+	network, err := job.network()
+	if err != nil {
+		cliLog(err.Error())
+		return 2
+	}
+	wallets, err := candy.ParseWallets(job.Wallets, cliLog)
+	if err != nil {
+		cliLog(err.Error())
+		return 1
+	}
+	cliLog(fmt.Sprintf("导入目标钱包完成，共导入 %d 个钱包地址", len(wallets)))
+	o := shared.Options()
+	o.Token = job.Token
+	o.Network = network
+	o.Amount = job.Amount
+	o.Wallets = wallets
+	o.WalletsFile = job.Wallets
+	o.Fee = job.Fee
+	o.MaxFee = job.MaxFee
+	o.MaxTip = job.MaxTip
+	o.Batch = *batchMode
+	o.Disperse = *disperseAddr
+	o.MerkleOut = *merkleOut
+	o.MerkleDeploy = *merkleDeploy
+	run := candy.Distribute
+	switch {
+	case *dryRunFlag:
+		run = candy.DryRun
+	case *merkleMode:
+		run = candy.MerkleAirdrop
+	}
+	if err := run(signer, o, cliLog); err != nil {
+		cliLog(err.Error())
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	flag.Parse()
+	if *exportKeystore != "" {
+		os.Exit(runExport())
+	}
+	os.Exit(runCLI())
+}
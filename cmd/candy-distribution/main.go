@@ -1,27 +1,27 @@
 package main
 
 import (
-	"bufio"
-	"crypto/ecdsa"
+	"flag"
 	"fmt"
-	"math"
-	"math/big"
 	"os"
-	"strconv"
 	"time"
 
 	"github.com/andlabs/ui"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/naiba/eth-tools/internal/erc20"
+	"github.com/naiba/eth-tools/internal/airdrop"
+	"github.com/naiba/eth-tools/internal/candy"
 	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/keys"
+	"github.com/naiba/eth-tools/internal/netconf"
 	"github.com/naiba/eth-tools/internal/uiutil"
+	"github.com/naiba/eth-tools/internal/units"
 )
 
-var targetWalletsFile string
-var targetWallets []common.Address
+// shared 与命令行版本共用的参数
+var shared = candy.RegisterFlags(flag.CommandLine)
+
+// headless 命令行模式已移到 candy-distribution-cli，保留参数以提示旧脚本
+var headless = flag.Bool("cli", false, "已废弃，命令行模式请使用 candy-distribution-cli")
+
 var logEntry *ui.MultilineEntry
 
 func setupUI() {
@@ -38,40 +38,202 @@ func setupUI() {
 	mainBox := ui.NewVerticalBox()
 	mainBox.SetPadded(true)
 
-	pkEntry, pkBox := uiutil.GetEntry("钱包私钥")
-	mainBox.Append(pkBox, false)
+	cfg, err := netconf.Load(shared.Networks)
+	if err != nil {
+		ui.MsgBoxError(mainwin, "读取网络配置失败", err.Error())
+		ui.Quit()
+		return
+	}
+	picker := uiutil.NewNetworkPicker(cfg)
+	picker.Select(shared.Network)
+	mainBox.Append(picker.Box, false)
+
+	keyPicker := uiutil.NewKeyPicker(mainwin, keys.Spec{Kind: keys.KindKeystore})
+	mainBox.Append(keyPicker.Box, false)
+	keyPicker.SetNetwork(picker)
 
 	tkEntry, tkBox := uiutil.GetEntry("代币地址")
 	mainBox.Append(tkBox, false)
 
 	amountEntry, amountBox := uiutil.GetEntry("分发数量")
 	mainBox.Append(amountBox, false)
+	// 分发数量可以留空，此时每个钱包的数量以钱包文件为准
+	readAmount := func() (units.Amount, bool) {
+		text := amountEntry.Text()
+		if text == "" {
+			return units.Amount{}, true
+		}
+		amount, err := units.ParseAmount(text)
+		if err != nil || amount.Sign() <= 0 {
+			appendLog("分发数量有误：" + text)
+			return units.Amount{}, false
+		}
+		return amount, true
+	}
 
+	// 导入的钱包只在界面线程读写，开始分发时复制到分发设置中
+	var walletsFile string
+	var wallets []airdrop.Recipient
 	dbBox := ui.NewHorizontalBox()
 	dbLb := ui.NewLabel("请点击左侧按钮导入")
 	dbBtn := ui.NewButton("导入用户钱包")
 	dbBtn.OnClicked(func(b *ui.Button) {
 		dbBtn.Disable()
-		targetWalletsFile = ui.OpenFile(mainwin)
-		dbLb.SetText(targetWalletsFile)
-		go parseWallets(b)
+		path := ui.OpenFile(mainwin)
+		dbLb.SetText(path)
+		walletsFile, wallets = path, nil
+		go func() {
+			defer ui.QueueMain(b.Enable)
+			parsed, err := candy.ParseWallets(path, appendLog)
+			if err != nil {
+				appendLog(err.Error())
+				return
+			}
+			ui.QueueMain(func() {
+				if walletsFile == path {
+					wallets = parsed
+				}
+			})
+			appendLog(fmt.Sprintf("导入目标钱包完成，共导入 %d 个钱包地址", len(parsed)))
+		}()
 	})
 	dbBox.Append(dbBtn, false)
 	dbBox.Append(dbLb, true)
 	mainBox.Append(dbBox, false)
 
+	batchBox := ui.NewHorizontalBox()
+	batchBox.SetPadded(true)
+	batchCheck := ui.NewCheckbox("批量合约分发")
+	disperseEntry, disperseBox := uiutil.GetEntry("合约地址")
+	batchBox.Append(batchCheck, false)
+	batchBox.Append(disperseBox, true)
+	mainBox.Append(batchBox, false)
+
+	feeBox := ui.NewHorizontalBox()
+	feeBox.SetPadded(true)
+	feeCombo := ui.NewCombobox()
+	for _, name := range ethutil.FeeModeNames {
+		feeCombo.Append(name)
+	}
+	feeCombo.SetSelected(0)
+	maxFeeEntry, maxFeeBox := uiutil.GetEntry("最高费用(Gwei)")
+	maxTipEntry, maxTipBox := uiutil.GetEntry("最高小费(Gwei)")
+	feeBox.Append(feeCombo, false)
+	feeBox.Append(maxFeeBox, true)
+	feeBox.Append(maxTipBox, true)
+	mainBox.Append(feeBox, false)
+	// 切换网络时使用该网络默认的交易类型
+	selectFee := func(p netconf.Profile) {
+		for i, mode := range ethutil.FeeModes {
+			if mode == p.Fee {
+				feeCombo.SetSelected(i)
+			}
+		}
+	}
+	picker.OnSelected(selectFee)
+	selectFee(picker.Selected())
+	// readOptions 在界面线程读取本次分发的设置，分发数量有误时返回 nil
+	readOptions := func() *candy.Options {
+		amount, ok := readAmount()
+		if !ok {
+			return nil
+		}
+		o := shared.Options()
+		o.Token = tkEntry.Text()
+		o.Network = picker.Selected()
+		o.Amount = amount
+		o.Wallets = wallets
+		o.WalletsFile = walletsFile
+		o.MaxFee = maxFeeEntry.Text()
+		o.MaxTip = maxTipEntry.Text()
+		o.Batch = batchCheck.Checked()
+		o.Disperse = disperseEntry.Text()
+		if i := feeCombo.Selected(); i >= 0 {
+			o.Fee = string(ethutil.FeeModes[i])
+		}
+		return o
+	}
+
 	doBtn := ui.NewButton("分发糖果")
 	doBtn.OnClicked(func(b *ui.Button) {
 		b.Disable()
-		amount, _ := strconv.ParseInt(amountEntry.Text(), 10, 64)
-		if amount <= 0 {
+		o := readOptions()
+		if o == nil {
+			b.Enable()
+			return
+		}
+		spec, secret := keyPicker.Spec(), keyPicker.Secret()
+		go func() {
+			defer ui.QueueMain(func() {
+				// 新部署的批量合约地址回填到界面，下次直接复用
+				disperseEntry.SetText(o.Disperse)
+				b.Enable()
+			})
+			signer, err := candy.OpenSigner(spec, secret, appendLog)
+			if err != nil {
+				appendLog(err.Error())
+				return
+			}
+			if err := candy.Distribute(signer, o, appendLog); err != nil {
+				appendLog(err.Error())
+			}
+		}()
+	})
+	dryRunBtn := ui.NewButton("预演")
+	dryRunBtn.OnClicked(func(b *ui.Button) {
+		b.Disable()
+		o := readOptions()
+		if o == nil {
+			b.Enable()
+			return
+		}
+		spec, secret := keyPicker.Spec(), keyPicker.Secret()
+		go func() {
+			defer ui.QueueMain(b.Enable)
+			signer, err := candy.OpenSigner(spec, secret, appendLog)
+			if err != nil {
+				appendLog(err.Error())
+				return
+			}
+			if err := candy.DryRun(signer, o, appendLog); err != nil {
+				appendLog(err.Error())
+			}
+		}()
+	})
+	doBox := ui.NewHorizontalBox()
+	doBox.SetPadded(true)
+	doBox.Append(dryRunBtn, false)
+	doBox.Append(doBtn, true)
+	mainBox.Append(doBox, false)
+
+	merkleBox := ui.NewHorizontalBox()
+	merkleBox.SetPadded(true)
+	merkleDeployCheck := ui.NewCheckbox("部署领取合约并转入代币")
+	merkleBtn := ui.NewButton("生成领取证明")
+	merkleBtn.OnClicked(func(b *ui.Button) {
+		b.Disable()
+		o := readOptions()
+		if o == nil {
 			b.Enable()
-			appendLog("分发数量有误：" + amountEntry.Text())
 			return
 		}
-		go distribution(b, pkEntry.Text(), tkEntry.Text(), amount)
+		o.MerkleDeploy = merkleDeployCheck.Checked()
+		spec, secret := keyPicker.Spec(), keyPicker.Secret()
+		go func() {
+			defer ui.QueueMain(b.Enable)
+			signer, err := candy.OpenSigner(spec, secret, appendLog)
+			if err != nil {
+				appendLog(err.Error())
+				return
+			}
+			if err := candy.MerkleAirdrop(signer, o, appendLog); err != nil {
+				appendLog(err.Error())
+			}
+		}()
 	})
-	mainBox.Append(doBtn, false)
+	merkleBox.Append(merkleBtn, true)
+	merkleBox.Append(merkleDeployCheck, false)
+	mainBox.Append(merkleBox, false)
 
 	logEntry = ui.NewMultilineEntry()
 	logEntry.SetReadOnly(true)
@@ -81,80 +243,18 @@ func setupUI() {
 	mainwin.Show()
 }
 
+// appendLog 输出一条分发日志到日志框
 func appendLog(msg string) {
 	ui.QueueMain(func() {
 		logEntry.SetText(time.Now().Format("15:04:05") + "：" + msg + "\n" + logEntry.Text())
 	})
 }
 
-func parseWallets(btn *ui.Button) {
-	defer ui.QueueMain(func() {
-		btn.Enable()
-		appendLog(fmt.Sprintf("导入目标钱包完成，共导入 %d 个钱包地址", len(targetWallets)))
-	})
-	targetWallets = make([]common.Address, 0)
-	file, err := os.OpenFile(targetWalletsFile, os.O_RDONLY, os.ModePerm)
-	if err != nil {
-		appendLog(fmt.Sprintf("打开文件失败：%s,%s", targetWalletsFile, err))
-		return
-	}
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		targetWallets = append(targetWallets, common.HexToAddress(scanner.Text()))
-	}
-	if err := scanner.Err(); err != nil {
-		appendLog(fmt.Sprintf("读取文件失败：%s,%s", targetWalletsFile, err))
-		return
-	}
-}
-
-func distribution(btn *ui.Button, pk, token string, amount int64) {
-	defer btn.Enable()
-	network := "wss://mainnet.infura.io/ws/v3/c520f3240b964adc94750241a96bd328"
-	client, err := ethclient.Dial(network)
-	for err != nil {
-		appendLog(fmt.Sprintf("网络错误，正在重连：%s", err))
-		time.Sleep(time.Second * 1)
-		client, err = ethclient.Dial(network)
-	}
-	privateKey, err := crypto.HexToECDSA(pk)
-	if err != nil {
-		appendLog(fmt.Sprintf("解析私钥错误：%s", err))
-		return
-	}
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		appendLog("解析公钥错误")
-		return
-	}
-	wallet := crypto.PubkeyToAddress(*publicKeyECDSA)
-	tokenAddr := common.HexToAddress(token)
-
-	tokenContract, err := erc20.NewErc20(tokenAddr, client)
-	if err != nil {
-		appendLog(fmt.Sprintf("代币错误：%s", err))
-		return
-	}
-	decimal, err := tokenContract.Decimals(&bind.CallOpts{
-		From: wallet,
-	})
-	if err != nil {
-		appendLog(fmt.Sprintf("获取代币小数位数错误：%s", err))
-		return
-	}
-	bnAmount := big.NewInt(amount)
-	bnAmount = bnAmount.Mul(bnAmount, big.NewInt(int64(math.Pow10(int(decimal)))))
-	for i := 0; i < len(targetWallets); i++ {
-		tx, err := tokenContract.Transfer(ethutil.GenerateTransactOpts(client, privateKey, wallet), targetWallets[i], bnAmount)
-		if err != nil {
-			appendLog(fmt.Sprintf("糖果分发错误：钱包-%s,Transaction-%s,Error-%s", targetWallets[i], tx.Hash().String(), err))
-		} else {
-			appendLog(fmt.Sprintf("糖果分发成功：钱包-%s,Transaction-%s,数量-%d", targetWallets[i], tx.Hash().String(), amount))
-		}
-	}
-}
-
 func main() {
+	flag.Parse()
+	if *headless {
+		fmt.Fprintln(os.Stderr, "命令行模式已移到 candy-distribution-cli，参数与任务文件不变")
+		os.Exit(2)
+	}
 	ui.Main(setupUI)
 }
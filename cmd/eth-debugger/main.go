@@ -2,35 +2,33 @@ package main
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
-	"math/big"
-	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
-	"github.com/naiba/eth-tools/internal/erc20"
 	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/faucet"
+	"github.com/naiba/eth-tools/internal/keys"
+	"github.com/naiba/eth-tools/internal/netconf"
 	"github.com/naiba/eth-tools/internal/uiutil"
+	"github.com/naiba/eth-tools/internal/units"
 
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/andlabs/ui"
 	_ "github.com/andlabs/ui/winmanifest"
 )
 
-var networks = []string{
-	"Kovan #wss://kovan.infura.io/ws",
-	"Ropsten #wss://ropsten.infura.io/ws",
-	"NBTestNet #ws://tokenbank.tk:7545/ws",
-	"DBLTestNet #ws://120.55.15.98:9527/ws",
-}
+var networksFile = flag.String("networks", "", "网络配置文件，默认为用户配置目录下的 eth-tools/networks.json")
+
+// maxETH 单次最多领取的 ETH（wei）
+var maxETH = units.MustParseEther("0.001")
+
+// dialAttempts 连接节点的最多尝试次数
+const dialAttempts = 10
 
 func setupUI() {
 	mainwin := ui.NewWindow("ETH 调试工具", 300, 418, true)
@@ -47,15 +45,51 @@ func setupUI() {
 	mainBox.SetPadded(true)
 
 	// ============= Choose Network =============
-	networkBox := ui.NewHorizontalBox()
-	networkBox.SetPadded(true)
-	networkLabel := ui.NewLabel("选择节点")
-	networkCombo := ui.NewCombobox()
-	for i := 0; i < len(networks); i++ {
-		networkCombo.Append(networks[i])
-	}
-	networkBox.Append(networkLabel, false)
-	networkBox.Append(networkCombo, true)
+	cfg, err := netconf.Load(*networksFile)
+	if err != nil {
+		ui.MsgBoxError(mainwin, "读取网络配置失败", err.Error())
+		ui.Quit()
+		return
+	}
+	// ============= Fee =============
+	feeBox := ui.NewHorizontalBox()
+	feeBox.SetPadded(true)
+	feeCombo := ui.NewCombobox()
+	for _, name := range ethutil.FeeModeNames {
+		feeCombo.Append(name)
+	}
+	feeCombo.SetSelected(0)
+	maxFeeEntry, maxFeeBox := uiutil.GetEntry("最高费用(Gwei)")
+	maxTipEntry, maxTipBox := uiutil.GetEntry("最高小费(Gwei)")
+	feeBox.Append(feeCombo, false)
+	feeBox.Append(maxFeeBox, true)
+	feeBox.Append(maxTipBox, true)
+	// 切换网络时使用该网络默认的交易类型
+	picker := uiutil.NewNetworkPicker(cfg)
+	selectFee := func(p netconf.Profile) {
+		for i, mode := range ethutil.FeeModes {
+			if mode == p.Fee {
+				feeCombo.SetSelected(i)
+			}
+		}
+	}
+	picker.OnSelected(selectFee)
+	selectFee(picker.Selected())
+	readFee := func() (ethutil.FeeConfig, error) {
+		cfg := ethutil.FeeConfig{Mode: ethutil.FeeAuto}
+		if i := feeCombo.Selected(); i >= 0 {
+			cfg.Mode = ethutil.FeeModes[i]
+		}
+		var err error
+		if cfg.MaxFeeCap, err = ethutil.ParseGwei(maxFeeEntry.Text()); err != nil {
+			return cfg, err
+		}
+		cfg.MaxTipCap, err = ethutil.ParseGwei(maxTipEntry.Text())
+		return cfg, err
+	}
+	// ============= Faucet Key =============
+	keyPicker := uiutil.NewKeyPicker(mainwin, keys.Spec{Kind: keys.KindEnv, Location: "FAUCET_PRIVATE_KEY"})
+	keyPicker.SetNetwork(picker)
 	// ============= Set Token Info =============
 	walletEntry, walletBox := uiutil.GetEntry("钱包地址")
 	numEntry, numBox := uiutil.GetEntry("领取数量")
@@ -69,10 +103,13 @@ func setupUI() {
 		if !b.Enabled() {
 			return
 		}
+		fee, err := readFee()
+		if err != nil {
+			ui.MsgBoxError(mainwin, "手续费设置错误", err.Error())
+			return
+		}
 		b.Disable()
-		num, _ := strconv.ParseInt(numEntry.Text(), 10, 64)
-		network := strings.Split(networks[networkCombo.Selected()], "#")
-		go getToken(false, "01491231C2C71D99A16C7FB2120E185EAAE0861548B5FBF971859099DAB5DCA2", tokenEntry.Text(), walletEntry.Text(), network[1], num, b, mainwin)
+		go getToken(false, keyPicker.Spec(), keyPicker.Secret(), tokenEntry.Text(), walletEntry.Text(), picker.Selected(), fee, numEntry.Text(), b, mainwin)
 	})
 	getTokenBox.Append(tokenBox, true)
 	getTokenBox.Append(getBtn, true)
@@ -86,25 +123,72 @@ func setupUI() {
 		if !b.Enabled() {
 			return
 		}
-		num, _ := strconv.ParseInt(numEntry.Text(), 10, 64)
-		if num > 10 || num < 1 {
-			ui.MsgBox(mainwin, "数量错误", "数量在 1 到 100 之间")
+		amount, err := units.ParseEther(numEntry.Text())
+		if err != nil {
+			ui.MsgBoxError(mainwin, "数量错误", err.Error())
+			return
+		}
+		if amount.Sign() <= 0 || amount.Cmp(maxETH) > 0 {
+			ui.MsgBox(mainwin, "数量错误", fmt.Sprintf("数量在 0 到 %s ETH 之间", units.FormatEther(maxETH)))
+			return
+		}
+		fee, err := readFee()
+		if err != nil {
+			ui.MsgBoxError(mainwin, "手续费设置错误", err.Error())
 			return
 		}
 		b.Disable()
-		network := strings.Split(networks[networkCombo.Selected()], "#")
-		go getToken(true, "01491231C2C71D99A16C7FB2120E185EAAE0861548B5FBF971859099DAB5DCA2", tokenEntry.Text(), walletEntry.Text(), network[1], num, b, mainwin)
+		go getToken(true, keyPicker.Spec(), keyPicker.Secret(), tokenEntry.Text(), walletEntry.Text(), picker.Selected(), fee, numEntry.Text(), b, mainwin)
 	})
 	getETHBox.Append(qiongbiBtn, true)
-	chargeBtn := ui.NewButton("充值ETH")
-	chargeBtn.OnClicked(func(b *ui.Button) {
+
+	// ============= Top Up =============
+	topUpBox := ui.NewVerticalBox()
+	topUpBox.SetPadded(true)
+	treasuryPicker := uiutil.NewKeyPicker(mainwin, keys.Spec{Kind: keys.KindEnv, Location: "TREASURY_PRIVATE_KEY"})
+	treasuryPicker.SetNetwork(picker)
+	ethThresholdEntry, ethThresholdBox := uiutil.GetEntry("ETH 阈值")
+	ethThresholdEntry.SetText("1")
+	ethTargetEntry, ethTargetBox := uiutil.GetEntry("ETH 目标")
+	ethTargetEntry.SetText("5")
+	tokenThresholdEntry, tokenThresholdBox := uiutil.GetEntry("代币阈值")
+	tokenTargetEntry, tokenTargetBox := uiutil.GetEntry("代币目标")
+	ethRuleBox := ui.NewHorizontalBox()
+	ethRuleBox.SetPadded(true)
+	ethRuleBox.Append(ethThresholdBox, true)
+	ethRuleBox.Append(ethTargetBox, true)
+	tokenRuleBox := ui.NewHorizontalBox()
+	tokenRuleBox.SetPadded(true)
+	tokenRuleBox.Append(tokenThresholdBox, true)
+	tokenRuleBox.Append(tokenTargetBox, true)
+	topUpBtn := ui.NewButton("从国库补充")
+	topUpBtn.OnClicked(func(b *ui.Button) {
 		if !b.Enabled() {
 			return
 		}
+		in := topUpInput{
+			ethThreshold:   ethThresholdEntry.Text(),
+			ethTarget:      ethTargetEntry.Text(),
+			token:          tokenEntry.Text(),
+			tokenThreshold: tokenThresholdEntry.Text(),
+			tokenTarget:    tokenTargetEntry.Text(),
+		}
+		if err := in.check(); err != nil {
+			ui.MsgBoxError(mainwin, "补充规则错误", err.Error())
+			return
+		}
+		fee, err := readFee()
+		if err != nil {
+			ui.MsgBoxError(mainwin, "手续费设置错误", err.Error())
+			return
+		}
 		b.Disable()
-		go charge(b, mainwin)
+		go topUp(keyPicker.Spec(), keyPicker.Secret(), treasuryPicker.Spec(), treasuryPicker.Secret(), picker.Selected(), fee, in, b, mainwin)
 	})
-	getETHBox.Append(chargeBtn, true)
+	topUpBox.Append(treasuryPicker.Box, false)
+	topUpBox.Append(ethRuleBox, false)
+	topUpBox.Append(tokenRuleBox, false)
+	topUpBox.Append(topUpBtn, false)
 
 	// ============= A Tab =============
 	mainTab := ui.NewTab()
@@ -113,27 +197,33 @@ func setupUI() {
 	tipsLb := ui.NewMultilineEntry()
 	tipsLb.SetReadOnly(true)
 	tipsLb.SetText("前置操作：\n" +
-		"1.填写你的钱包地址\n" +
-		"2.填写需要领取的数量（领取代币无限制，ETH 限额为 1-100）\n" +
+		"1.选择水龙头的签名密钥，私钥不会保存在程序中\n" +
+		"2.填写你的钱包地址\n" +
+		"3.填写需要领取的数量，如 0.0005（ETH 可以带单位，如 100 gwei；代币按代币的小数位数换算，不能带单位，不限制数量；ETH 单次最多 " + units.FormatEther(maxETH) + "）\n" +
 		"领取代币：\n" +
 		"1.填写代币地址\n" +
 		"领取ETH：\n" +
 		"1.点击「穷逼领钱」按钮\n" +
-		"2.如果领取不成功点击「充值ETH」充值库存",
+		"补充库存：\n" +
+		"1.选择国库的签名密钥，水龙头余额低于阈值时从国库补充到目标值\n" +
+		"2.阈值与目标填写 ETH 或代币的数量，填写了代币地址与代币阈值时同时补充代币，代币允许国库铸造时铸造，否则从国库转账",
 	)
 
-	mainBox.Append(networkBox, false) //选择网络
-	mainBox.Append(walletBox, false)  // 钱包地址
-	mainBox.Append(numBox, false)     // 设置数量
+	mainBox.Append(picker.Box, false)    //选择网络
+	mainBox.Append(feeBox, false)        // 手续费
+	mainBox.Append(keyPicker.Box, false) // 水龙头密钥
+	mainBox.Append(walletBox, false)     // 钱包地址
+	mainBox.Append(numBox, false)        // 设置数量
 	mainTab.Append("获取代币", getTokenBox)
 	mainTab.Append("获取ETH", getETHBox)
+	mainTab.Append("补充库存", topUpBox)
 	mainBox.Append(mainTab, false) // 领取 ETH 或 代币
 	mainBox.Append(tipsLb, true)   // 使用说明
 	mainwin.SetChild(mainBox)
 	mainwin.Show()
 }
 
-func getToken(isETH bool, pk, tokenAddr, walletAddr, network string, num int64, btn *ui.Button, win *ui.Window) {
+func getToken(isETH bool, key keys.Spec, secret keys.Secret, tokenAddr, walletAddr string, network netconf.Profile, fee ethutil.FeeConfig, amount string, btn *ui.Button, win *ui.Window) {
 	setTitle := func(t string) {
 		go ui.QueueMain(func() {
 			win.SetTitle("Token 获取器：" + t)
@@ -145,87 +235,143 @@ func getToken(isETH bool, pk, tokenAddr, walletAddr, network string, num int64,
 			btn.Enable()
 		})
 	}()
+	req := faucet.Request{To: common.HexToAddress(walletAddr)}
+	if !common.IsHexAddress(walletAddr) {
+		setTitle("获取失败 钱包地址有误")
+		return
+	}
+	if !isETH {
+		if !common.IsHexAddress(tokenAddr) {
+			setTitle("获取失败 代币地址有误")
+			return
+		}
+		req.Token = common.HexToAddress(tokenAddr)
+	}
 	setTitle("正在连接节点")
-	client, err := ethclient.Dial(network)
-	for err != nil {
+	rc, _, err := network.DialRetry(context.Background(), dialAttempts, func(err error) {
 		log.Println(err)
-		time.Sleep(time.Second * 1)
-		client, err = ethclient.Dial(network)
+	})
+	if err != nil {
+		setTitle(fmt.Sprintf("获取失败 %s", err))
+		return
 	}
-	setTitle("连接节点成功，解析密钥")
-	TBCAdminPk, err := crypto.HexToECDSA(pk)
+	setTitle("打开水龙头密钥")
+	signer, err := keys.Open(key, secret)
 	if err != nil {
-		setTitle(fmt.Sprint("获取失败", "HexToECDSA", err))
+		rc.Close()
+		setTitle(fmt.Sprint("获取失败 ", err))
 		return
 	}
-	publicKey := TBCAdminPk.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		setTitle(fmt.Sprint("获取失败", "cannot assert type: publicKey is not of type *ecdsa.PublicKey"))
+	setTitle("连接节点成功，核对链 ID")
+	f, err := faucet.New(context.Background(), rc, faucet.Config{Network: network, Signer: signer, Fee: fee})
+	if err != nil {
+		rc.Close()
+		warnChain(win, network, err)
+		setTitle(fmt.Sprintf("获取失败 %s", err))
+		return
+	}
+	defer f.Close()
+	// 数量按资产的小数位数换算成最小单位
+	decimals, err := f.Decimals(context.Background(), req.Token)
+	if err != nil {
+		setTitle(fmt.Sprintf("获取失败 %s", err))
 		return
 	}
-	TBCAdminPub := crypto.PubkeyToAddress(*publicKeyECDSA)
-
 	if isETH {
-		if !strings.Contains(network, "ropsten") {
-			setTitle("此网络无法进行充值")
-			return
-		}
-		opt := ethutil.GenerateTransactOpts(client, TBCAdminPk, TBCAdminPub)
-		opt.Value = big.NewInt(num * 10000000000000) // in wei (1 eth)
-		var data []byte
-		tx := types.NewTransaction(opt.Nonce.Uint64(), common.HexToAddress(walletAddr), opt.Value, opt.GasLimit, opt.GasPrice, data)
+		req.Amount, err = units.ParseEther(amount)
+	} else {
+		req.Amount, err = units.ParseToken(amount, decimals)
+	}
+	if err != nil {
+		setTitle(fmt.Sprintf("获取失败 %s", err))
+		return
+	}
 
-		chainID, err := client.NetworkID(context.Background())
-		if err != nil {
+	setTitle("正在发送交易并等待确认")
+	res, err := f.Dispense(context.Background(), req)
+	if err != nil {
+		warnChain(win, network, err)
+		var ferr *faucet.Error
+		if errors.As(err, &ferr) && ferr.Tx != (common.Hash{}) {
+			setTitle(fmt.Sprintf("获取失败 %s %s", err, network.TxURL(ferr.Tx.Hex())))
+		} else {
 			setTitle(fmt.Sprintf("获取失败 %s", err))
-			return
 		}
+		return
+	}
+	setTitle("发送成功 " + res.Link)
+	if !isETH && res.Balance != nil {
+		time.Sleep(time.Second * 2)
+		setTitle(fmt.Sprintf("恭喜您，您当前余额为：%s。", units.Format(res.Balance, decimals)))
+	}
 
-		setTitle("Transcation 签名")
-		signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), TBCAdminPk)
-		if err != nil {
-			setTitle(fmt.Sprintf("获取失败 %s", err))
-			return
-		}
+	time.Sleep(time.Second * 4)
+}
 
-		setTitle("广播 Transcation")
-		err = client.SendTransaction(context.Background(), signedTx)
+// warnChain 节点的链 ID 与网络配置不符时弹窗警告
+func warnChain(win *ui.Window, network netconf.Profile, err error) {
+	var mismatch *ethutil.ChainMismatchError
+	if !errors.As(err, &mismatch) {
+		return
+	}
+	ui.QueueMain(func() {
+		ui.MsgBoxError(win, "链 ID 不符", fmt.Sprintf("%s 的节点不是预期的链，请检查网络配置。\n%s", network.Name, err))
+	})
+}
+
+// topUpInput 界面上填写的补充阈值与目标，留空的资产不补充
+type topUpInput struct {
+	ethThreshold, ethTarget     string // ETH 数量，可以带单位
+	token                       string
+	tokenThreshold, tokenTarget string // 代币数量，连接节点后按代币的小数位数换算
+}
+
+// check 连接节点前检查填写的内容
+func (in topUpInput) check() error {
+	if in.ethThreshold == "" && in.ethTarget == "" && in.tokenThreshold == "" && in.tokenTarget == "" {
+		return errors.New("请填写 ETH 或代币的阈值与目标")
+	}
+	if (in.tokenThreshold != "" || in.tokenTarget != "") && !common.IsHexAddress(in.token) {
+		return errors.New("补充代币需要在「获取代币」中填写代币地址")
+	}
+	return nil
+}
+
+// rules 生成补充规则，代币的阈值与目标按链上的小数位数换算
+func (in topUpInput) rules(ctx context.Context, f *faucet.Faucet) ([]faucet.TopUpRule, error) {
+	var rules []faucet.TopUpRule
+	if in.ethThreshold != "" || in.ethTarget != "" {
+		threshold, err := units.ParseEther(in.ethThreshold)
 		if err != nil {
-			setTitle(fmt.Sprintf("获取失败 %s", err))
-			return
+			return nil, fmt.Errorf("ETH 阈值有误：%s", err)
 		}
-		setTitle("发送成功")
-	} else {
-		token, err := erc20.NewErc20(common.HexToAddress(tokenAddr), client)
+		target, err := units.ParseEther(in.ethTarget)
 		if err != nil {
-			setTitle(fmt.Sprint("获取失败", "NewErc20", err))
-			return
+			return nil, fmt.Errorf("ETH 目标有误：%s", err)
 		}
-		setTitle("解析成功，正在获取代币")
-		tx, err := token.AddToken(ethutil.GenerateTransactOpts(client, TBCAdminPk, TBCAdminPub), common.HexToAddress(walletAddr), big.NewInt(num*10000000000))
+		rules = append(rules, faucet.TopUpRule{Threshold: threshold, Target: target})
+	}
+	if in.tokenThreshold != "" || in.tokenTarget != "" {
+		token := common.HexToAddress(in.token)
+		decimals, err := f.Decimals(ctx, token)
 		if err != nil {
-			setTitle(fmt.Sprint("获取失败", "AddToken", err))
-			return
+			return nil, err
 		}
-		_, _, err = ethutil.TrackTxResult(client, tx.Hash())
+		threshold, err := units.ParseToken(in.tokenThreshold, decimals)
 		if err != nil {
-			setTitle(fmt.Sprint("获取失败", "TrackTxResult", err))
-			return
+			return nil, fmt.Errorf("代币阈值有误：%s", err)
 		}
-		setTitle("获取代币成功")
-		tmp, err := token.BalanceOf(&bind.CallOpts{}, common.HexToAddress(walletAddr))
+		target, err := units.ParseToken(in.tokenTarget, decimals)
 		if err != nil {
-			setTitle(fmt.Sprint("获取失败", "BalanceOf", err))
-			return
+			return nil, fmt.Errorf("代币目标有误：%s", err)
 		}
-		setTitle(fmt.Sprintf("恭喜您，您当前余额为：%d。", tmp.Uint64()))
+		rules = append(rules, faucet.TopUpRule{Token: token, Threshold: threshold, Target: target})
 	}
-
-	time.Sleep(time.Second * 4)
+	return rules, nil
 }
 
-func charge(btn *ui.Button, win *ui.Window) {
+// topUp 检查水龙头账户的余额，不足时从国库账户补充，每次补充都写入日志
+func topUp(key keys.Spec, secret keys.Secret, treasuryKey keys.Spec, treasurySecret keys.Secret, network netconf.Profile, fee ethutil.FeeConfig, in topUpInput, btn *ui.Button, win *ui.Window) {
 	setTitle := func(t string) {
 		go ui.QueueMain(func() {
 			win.SetTitle("Token 获取器：" + t)
@@ -237,16 +383,62 @@ func charge(btn *ui.Button, win *ui.Window) {
 			btn.Enable()
 		})
 	}()
-	setTitle("正在充值")
-	resp, err := http.Get("https://faucet.ropsten.be/donate/0x1f944B7F5aF34740541D438c75a93cD5200ef1c2")
-	if err != nil || resp.StatusCode != 200 {
-		setTitle("充值失败")
+	setTitle("打开水龙头与国库密钥")
+	signer, err := keys.Open(key, secret)
+	if err != nil {
+		setTitle(fmt.Sprint("补充失败 ", err))
+		return
+	}
+	treasury, err := keys.Open(treasuryKey, treasurySecret)
+	if err != nil {
+		setTitle(fmt.Sprint("补充失败 ", err))
+		return
+	}
+	setTitle("正在连接节点")
+	rc, _, err := network.DialRetry(context.Background(), dialAttempts, func(err error) {
+		log.Println(err)
+	})
+	if err != nil {
+		setTitle(fmt.Sprintf("补充失败 %s", err))
+		return
+	}
+	f, err := faucet.New(context.Background(), rc, faucet.Config{Network: network, Signer: signer, Fee: fee})
+	if err != nil {
+		rc.Close()
+		warnChain(win, network, err)
+		setTitle(fmt.Sprintf("补充失败 %s", err))
 		return
 	}
-	setTitle("充值成功")
-	time.Sleep(time.Second * 3)
+	defer f.Close()
+	rules, err := in.rules(context.Background(), f)
+	if err != nil {
+		setTitle(fmt.Sprintf("补充失败 %s", err))
+		return
+	}
+	tu, err := f.NewTopUpper(treasury, rules)
+	if err != nil {
+		setTitle(fmt.Sprintf("补充失败 %s", err))
+		return
+	}
+
+	setTitle("正在检查余额并补充")
+	list := tu.Check(context.Background())
+	if len(list) == 0 {
+		setTitle("余额充足，无需补充")
+		time.Sleep(time.Second * 3)
+		return
+	}
+	lines := make([]string, len(list))
+	for i, t := range list {
+		log.Println(t)
+		lines[i] = t.String()
+	}
+	ui.QueueMain(func() {
+		ui.MsgBox(win, "补充结果", strings.Join(lines, "\n"))
+	})
 }
 
 func main() {
+	flag.Parse()
 	ui.Main(setupUI)
 }
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/naiba/eth-tools/internal/faucet"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// assetETH 领取 ETH 时的资产名称
+const assetETH = "ETH"
+
+// 接口返回的错误代码，其余代码与 faucet.ErrorCode 相同
+const (
+	codeCooldown = "cooldown" // 冷却中
+	codeBusy     = "busy"     // 队列已满
+	codeNotFound = "not_found"
+	codeInternal = "internal"
+)
+
+// asset 可以领取的资产及单次上限
+type asset struct {
+	Name     string   // ETH 或代币地址
+	Max      *big.Int // 单次上限（最小单位）
+	Decimals int
+}
+
+// parse 把领取数量换算成最小单位，ETH 可以带单位，代币按小数位数换算且不能带单位
+func (a asset) parse(s string) (*big.Int, error) {
+	if strings.EqualFold(a.Name, assetETH) {
+		return units.ParseEther(s)
+	}
+	return units.ParseToken(s, a.Decimals)
+}
+
+// server 水龙头的 HTTP 接口
+type server struct {
+	st        *store
+	d         *dispatcher
+	f         *faucet.Faucet
+	assets    []asset
+	addrWait  time.Duration
+	ipWait    time.Duration
+	proxyHops int // 可信的反向代理层数
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handlePage)
+	mux.HandleFunc("/api/info", s.handleInfo)
+	mux.HandleFunc("/api/claims", s.handleClaim)
+	mux.HandleFunc("/api/claims/", s.handleStatus)
+	return mux
+}
+
+type apiError struct {
+	Error      string `json:"error"`
+	Code       string `json:"code"`
+	RetryAfter int64  `json:"retry_after,omitempty"` // 秒
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	writeJSON(w, status, apiError{Error: msg, Code: code})
+}
+
+// findAsset 按名称查找资产，空字符串表示 ETH
+func (s *server) findAsset(name string) (asset, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = assetETH
+	}
+	for _, a := range s.assets {
+		if strings.EqualFold(a.Name, name) {
+			return a, true
+		}
+	}
+	return asset{}, false
+}
+
+// clientIP 请求方的 IP。经过 proxyHops 层可信代理时，每层代理都在 X-Forwarded-For 末尾追加
+// 它看到的地址，因此从右往左第 proxyHops 个地址是最外层代理看到的 IP；更靠左的地址由客户端
+// 自己填写，不可信。地址不足 proxyHops 个时取最左边的地址。
+func (s *server) clientIP(r *http.Request) string {
+	if s.proxyHops > 0 {
+		var fwd []string
+		for _, h := range r.Header.Values("X-Forwarded-For") {
+			fwd = append(fwd, strings.Split(h, ",")...)
+		}
+		if len(fwd) > 0 {
+			i := len(fwd) - s.proxyHops
+			if i < 0 {
+				i = 0
+			}
+			if ip := net.ParseIP(strings.TrimSpace(fwd[i])); ip != nil {
+				return ip.String()
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+type infoAsset struct {
+	Asset string `json:"asset"`
+	Max   string `json:"max"` // 单次上限，十进制数量
+}
+
+type info struct {
+	Network         string      `json:"network"`
+	ChainID         uint64      `json:"chain_id"`
+	Symbol          string      `json:"symbol"`
+	Faucet          string      `json:"faucet"`
+	Assets          []infoAsset `json:"assets"`
+	AddressCooldown int64       `json:"address_cooldown"` // 秒
+	IPCooldown      int64       `json:"ip_cooldown"`      // 秒
+	Queue           int         `json:"queue"`
+}
+
+func (s *server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	network := s.f.Network()
+	in := info{
+		Network:         network.Name,
+		ChainID:         network.ChainID,
+		Symbol:          network.Symbol,
+		Faucet:          s.f.Address().Hex(),
+		AddressCooldown: int64(s.addrWait / time.Second),
+		IPCooldown:      int64(s.ipWait / time.Second),
+		Queue:           s.d.pending(),
+	}
+	for _, a := range s.assets {
+		in.Assets = append(in.Assets, infoAsset{Asset: a.Name, Max: units.Format(a.Max, a.Decimals)})
+	}
+	writeJSON(w, http.StatusOK, in)
+}
+
+type claimRequest struct {
+	Address string `json:"address"`
+	Asset   string `json:"asset"`  // ETH 或代币地址，留空为 ETH
+	Amount  string `json:"amount"` // 十进制数量，如 0.05，ETH 可以带单位，留空为单次上限
+}
+
+// handleClaim 提交领取请求，检查通过后排队，返回请求编号
+func (s *server) handleClaim(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, string(faucet.CodeInvalid), "只支持 POST")
+		return
+	}
+	var req claimRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, string(faucet.CodeInvalid), fmt.Sprintf("请求格式有误：%s", err))
+		return
+	}
+	if !common.IsHexAddress(req.Address) || common.HexToAddress(req.Address) == (common.Address{}) {
+		writeError(w, http.StatusBadRequest, string(faucet.CodeInvalid), "钱包地址有误")
+		return
+	}
+	a, ok := s.findAsset(req.Asset)
+	if !ok {
+		writeError(w, http.StatusBadRequest, string(faucet.CodeInvalid), fmt.Sprintf("不支持领取 %s", req.Asset))
+		return
+	}
+	amount := new(big.Int).Set(a.Max)
+	if req.Amount != "" {
+		v, err := a.parse(req.Amount)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, string(faucet.CodeInvalid), fmt.Sprintf("领取数量有误：%s", err))
+			return
+		}
+		if v.Sign() <= 0 {
+			writeError(w, http.StatusBadRequest, string(faucet.CodeInvalid), "领取数量有误")
+			return
+		}
+		if v.Cmp(a.Max) > 0 {
+			writeError(w, http.StatusBadRequest, string(faucet.CodeInvalid), fmt.Sprintf("单次最多领取 %s", units.Format(a.Max, a.Decimals)))
+			return
+		}
+		amount = v
+	}
+	if s.d.pending() >= cap(s.d.queue) {
+		writeError(w, http.StatusServiceUnavailable, codeBusy, "排队的请求太多，请稍后再试")
+		return
+	}
+
+	c := &claim{
+		Address:  common.HexToAddress(req.Address),
+		Asset:    a.Name,
+		Amount:   units.Format(amount, a.Decimals),
+		Decimals: a.Decimals,
+		IP:       s.clientIP(r),
+	}
+	retry, err := s.st.reserve(c, s.addrWait, s.ipWait)
+	if err != nil {
+		log.Println(err)
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
+		return
+	}
+	if retry > 0 {
+		secs := int64((retry + time.Second - 1) / time.Second)
+		w.Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+		writeJSON(w, http.StatusTooManyRequests, apiError{
+			Error:      fmt.Sprintf("领取太频繁，请 %s 后再试", time.Duration(secs)*time.Second),
+			Code:       codeCooldown,
+			RetryAfter: secs,
+		})
+		return
+	}
+	if !s.d.enqueue(c.ID) {
+		msg := "排队的请求太多，请稍后再试"
+		s.d.fail(c, &faucet.Error{Code: codeBusy, Err: errors.New(msg)})
+		writeError(w, http.StatusServiceUnavailable, codeBusy, msg)
+		return
+	}
+	log.Printf("领取请求 %d：%s 领取 %s %s，来自 %s", c.ID, c.Address.Hex(), c.Amount, c.Asset, c.IP)
+	writeJSON(w, http.StatusAccepted, c.view())
+}
+
+// handleStatus 查询领取请求：GET /api/claims/<编号>
+func (s *server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, "/api/claims/"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "领取请求不存在")
+		return
+	}
+	c, err := s.st.get(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
+		return
+	}
+	if c == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "领取请求不存在")
+		return
+	}
+	writeJSON(w, http.StatusOK, c.view())
+}
+
+// view 返回给用户的领取请求，不包含 IP
+func (c *claim) view() *claim {
+	v := *c
+	v.IP = ""
+	return &v
+}
+
+func (s *server) handlePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, pageHTML)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+func TestClientIP(t *testing.T) {
+	for _, c := range []struct {
+		hops int
+		xff  []string
+		want string
+	}{
+		{0, nil, "10.0.0.1"},
+		{0, []string{"1.1.1.1"}, "10.0.0.1"},
+		{1, nil, "10.0.0.1"},
+		{1, []string{"1.1.1.1"}, "1.1.1.1"},
+		// 客户端伪造的地址在左边，一层代理只信任最右边的地址
+		{1, []string{"6.6.6.6, 1.1.1.1"}, "1.1.1.1"},
+		{1, []string{"6.6.6.6", "1.1.1.1"}, "1.1.1.1"},
+		{2, []string{"6.6.6.6, 1.1.1.1, 2.2.2.2"}, "1.1.1.1"},
+		{3, []string{"1.1.1.1, 2.2.2.2"}, "1.1.1.1"},
+		{1, []string{"6.6.6.6, not-an-ip"}, "10.0.0.1"},
+		{1, []string{"2001:db8::1"}, "2001:db8::1"},
+	} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = "10.0.0.1:1234"
+		for _, v := range c.xff {
+			r.Header.Add("X-Forwarded-For", v)
+		}
+		s := &server{proxyHops: c.hops}
+		if got := s.clientIP(r); got != c.want {
+			t.Errorf("proxyHops=%d X-Forwarded-For=%q：%s，预期 %s", c.hops, c.xff, got, c.want)
+		}
+	}
+}
+
+func TestHandleClaim(t *testing.T) {
+	st := openTestStore(t, t.TempDir())
+	s := &server{
+		st:        st,
+		d:         newDispatcher(st, nil, 2),
+		assets:    []asset{{Name: assetETH, Max: units.MustParseEther("0.1"), Decimals: units.Ether}},
+		addrWait:  time.Hour,
+		ipWait:    time.Minute,
+		proxyHops: 1,
+	}
+	srv := httptest.NewServer(s.routes())
+	defer srv.Close()
+
+	const (
+		a = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+		b = "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"
+		c = "0x90F79bf6EB2c4f870365E785982E1f101E93b906"
+	)
+	// 依次提交，前面的请求会设置冷却、占用队列
+	tests := []struct {
+		name   string
+		ip     string
+		req    claimRequest
+		status int
+		code   string // 出错时预期的错误代码
+		msg    string // 出错时预期错误信息包含的内容
+		amount string // 成功时预期的领取数量
+	}{
+		{"超过单次上限", "1.1.1.1", claimRequest{Address: a, Amount: "0.2"}, http.StatusBadRequest, "invalid", "单次最多领取 0.1", ""},
+		{"带单位超过单次上限", "1.1.1.1", claimRequest{Address: a, Amount: "100000001 gwei"}, http.StatusBadRequest, "invalid", "单次最多领取 0.1", ""},
+		{"数量为 0", "1.1.1.1", claimRequest{Address: a, Amount: "0"}, http.StatusBadRequest, "invalid", "领取数量有误", ""},
+		{"地址有误", "1.1.1.1", claimRequest{Address: "0x1234"}, http.StatusBadRequest, "invalid", "钱包地址有误", ""},
+		{"零地址", "1.1.1.1", claimRequest{Address: "0x0000000000000000000000000000000000000000"}, http.StatusBadRequest, "invalid", "钱包地址有误", ""},
+		{"不支持的资产", "1.1.1.1", claimRequest{Address: a, Asset: "DAI"}, http.StatusBadRequest, "invalid", "不支持领取 DAI", ""},
+		// 被拒绝的请求不设置冷却
+		{"留空数量时领取单次上限", "1.1.1.1", claimRequest{Address: a}, http.StatusAccepted, "", "", "0.1"},
+		{"地址冷却中", "2.2.2.2", claimRequest{Address: a, Amount: "0.01"}, http.StatusTooManyRequests, codeCooldown, "领取太频繁，请 1h0m0s 后再试", ""},
+		{"IP 冷却中", "1.1.1.1", claimRequest{Address: b, Amount: "0.01"}, http.StatusTooManyRequests, codeCooldown, "领取太频繁，请 1m0s 后再试", ""},
+		{"等于单次上限", "2.2.2.2", claimRequest{Address: b, Asset: "eth", Amount: "0.1 ether"}, http.StatusAccepted, "", "", "0.1"},
+		{"队列已满", "3.3.3.3", claimRequest{Address: c, Amount: "0.01"}, http.StatusServiceUnavailable, codeBusy, "排队的请求太多", ""},
+	}
+	for _, tt := range tests {
+		body, _ := json.Marshal(tt.req)
+		r, _ := http.NewRequest(http.MethodPost, srv.URL+"/api/claims", strings.NewReader(string(body)))
+		r.Header.Set("X-Forwarded-For", tt.ip)
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s：状态码 %d（%s），预期 %d", tt.name, resp.StatusCode, data, tt.status)
+			continue
+		}
+		if tt.status == http.StatusAccepted {
+			var got claim
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Amount != tt.amount || got.Status != statusQueued || got.IP != "" {
+				t.Errorf("%s：领取 %s，状态 %s，IP %q，预期排队领取 %s 且不返回 IP", tt.name, got.Amount, got.Status, got.IP, tt.amount)
+			}
+			continue
+		}
+		var e apiError
+		if err := json.Unmarshal(data, &e); err != nil {
+			t.Fatal(err)
+		}
+		if e.Code != tt.code || !strings.Contains(e.Error, tt.msg) {
+			t.Errorf("%s：错误 %s %q，预期 %s 且包含 %q", tt.name, e.Code, e.Error, tt.code, tt.msg)
+		}
+		if tt.status == http.StatusTooManyRequests && (resp.Header.Get("Retry-After") == "" || e.RetryAfter <= 0) {
+			t.Errorf("%s：没有返回 Retry-After", tt.name)
+		}
+	}
+
+	// 排队的请求按提交顺序编号
+	for i, want := range []string{a, b} {
+		id := <-s.d.queue
+		got, err := st.get(id)
+		if err != nil || got == nil || got.Address.Hex() != want {
+			t.Errorf("第 %d 个排队的请求为 %v，预期 %s 的请求", i+1, got, want)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+// faucet-server 自建的测试网水龙头，提供 JSON 接口与一个简单的领取页面：
+//
+//	FAUCET_PRIVATE_KEY=... faucet-server -network Sepolia -listen :8080 -tokens 0x...
+//
+// 接口：
+//
+//	GET  /api/info          水龙头地址、可领取的资产、单次上限与冷却时间
+//	POST /api/claims        提交领取请求 {"address":"0x...","asset":"ETH","amount":"0.05"}，返回请求编号
+//	GET  /api/claims/<编号> 查询领取结果
+//
+// 同一个钱包地址、同一个 IP 对每种资产分别有冷却时间。所有请求由一个协程按顺序签名广播，
+// 领取记录与冷却保存在 LevelDB 中，重启后继续处理未完成的请求。
+// 指定 -treasury-source 后定期检查水龙头的余额，不足时从国库账户补充。
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/faucet"
+	"github.com/naiba/eth-tools/internal/keys"
+	"github.com/naiba/eth-tools/internal/netconf"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+var (
+	listenAddr   = flag.String("listen", "127.0.0.1:8080", "HTTP 监听地址")
+	dbPath       = flag.String("db", "faucet-db", "数据库目录，保存领取记录与冷却")
+	networksFile = flag.String("networks", "", "网络配置文件，默认为用户配置目录下的 eth-tools/networks.json")
+	networkName  = flag.String("network", "Sepolia", "网络配置中的网络名称")
+	rpcFlag      = flag.String("rpc", "", "节点地址，设置后代替网络配置中的节点地址")
+	keySource    = flag.String("key-source", "env", "密钥来源：keystore、vault、mnemonic、env、file 或 remote")
+	keyLoc       = flag.String("key-location", "FAUCET_PRIVATE_KEY", "密钥位置：keystore 或私钥文件路径、环境变量名、保管库文件、远程签名服务地址、助记词派生路径")
+	keyAccount   = flag.String("key-account", "", "保管库中的密钥名称，远程签名服务中的账户地址，或助记词派生的账户序号")
+	passEnv      = flag.String("passphrase-env", "FAUCET_PASSPHRASE", "读取密码的环境变量，为空时在终端输入")
+	mnemonicEnv  = flag.String("mnemonic-env", "FAUCET_MNEMONIC", "读取助记词的环境变量，为空时在终端输入")
+	feeMode      = flag.String("fee", "", "交易类型：auto、legacy 或 1559，留空使用网络配置中的交易类型")
+	maxFeeCap    = flag.String("max-fee", "", "最高 Gas 费用（Gwei），留空不限制")
+	maxTipCap    = flag.String("max-tip", "", "EIP-1559 交易的最高小费（Gwei），留空不限制")
+	ethMax       = flag.String("eth-max", "0.1", "单次最多领取的 ETH，可以带单位，如 0.1 或 100 gwei，为 0 时不发放 ETH")
+	tokenList    = flag.String("tokens", "", "可以领取的代币地址，多个用逗号分隔，水龙头账户需要有代币的 addToken 权限")
+	tokenMax     = flag.String("token-max", "1000", "单次最多领取的代币数量，按代币的小数位数换算")
+	addrCooldown = flag.Duration("address-cooldown", time.Hour*24, "同一钱包地址两次领取同一资产的间隔")
+	ipCooldown   = flag.Duration("ip-cooldown", time.Hour, "同一 IP 两次领取同一资产的间隔")
+	queueSize    = flag.Int("queue", 100, "最多排队的领取请求数")
+	txTimeout    = flag.Duration("tx-timeout", time.Minute*5, "每笔交易等待确认的最长时间")
+	proxyHops    = flag.Int("proxy-hops", 0, "水龙头之前的反向代理层数，按 X-Forwarded-For 从右往左第 N 个地址识别 IP；0 表示使用连接的 IP")
+	trustProxy   = flag.Bool("trust-proxy", false, "部署在一层反向代理之后，等同于 -proxy-hops 1")
+
+	treasurySource   = flag.String("treasury-source", "", "国库账户的密钥来源，设置后自动补充水龙头的库存：keystore、vault、mnemonic、env、file 或 remote")
+	treasuryLoc      = flag.String("treasury-location", "TREASURY_PRIVATE_KEY", "国库账户的密钥位置")
+	treasuryAccount  = flag.String("treasury-account", "", "国库账户在保管库中的名称，远程签名服务中的地址，或助记词派生的序号")
+	treasuryPassEnv  = flag.String("treasury-passphrase-env", "TREASURY_PASSPHRASE", "读取国库账户密码的环境变量，为空时在终端输入")
+	treasuryMnemonic = flag.String("treasury-mnemonic-env", "TREASURY_MNEMONIC", "读取国库账户助记词的环境变量，为空时在终端输入")
+	ethThreshold     = flag.String("topup-eth-threshold", "1", "水龙头的 ETH 低于该值时从国库补充，可以带单位")
+	ethTarget        = flag.String("topup-eth-target", "5", "从国库补充 ETH 到该值，可以带单位")
+	tokenThreshold   = flag.String("topup-token-threshold", "", "水龙头的代币数量低于该值时补充，留空不补充代币；代币允许国库账户铸造时铸造，否则从国库转账")
+	tokenTarget      = flag.String("topup-token-target", "", "补充代币到该数量")
+	topUpInterval    = flag.Duration("topup-interval", time.Minute, "检查水龙头余额的间隔")
+)
+
+func main() {
+	flag.Parse()
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	network, err := loadNetwork()
+	if err != nil {
+		return err
+	}
+	if *queueSize < 1 {
+		return fmt.Errorf("队列长度有误：%d", *queueSize)
+	}
+	fee, err := feeConfig(network)
+	if err != nil {
+		return err
+	}
+	signer, err := openSigner(*keySource, *keyLoc, *keyAccount, *passEnv, *mnemonicEnv)
+	if err != nil {
+		return err
+	}
+	var treasury ethutil.Signer
+	if *treasurySource != "" {
+		if treasury, err = openSigner(*treasurySource, *treasuryLoc, *treasuryAccount, *treasuryPassEnv, *treasuryMnemonic); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rc, url, err := network.Dial(ctx)
+	if err != nil {
+		return err
+	}
+	f, err := faucet.New(ctx, rc, faucet.Config{Network: network, Signer: signer, Fee: fee, Timeout: *txTimeout})
+	if err != nil {
+		rc.Close()
+		return err
+	}
+	defer f.Close()
+	log.Printf("已连接 %s（%s），水龙头账户 %s", network.Name, url, f.Address().Hex())
+	assets, err := parseAssets(ctx, f)
+	if err != nil {
+		return err
+	}
+	if treasury != nil {
+		rules, err := topUpRules(assets)
+		if err != nil {
+			return err
+		}
+		tu, err := f.NewTopUpper(treasury, rules)
+		if err != nil {
+			return err
+		}
+		log.Printf("国库账户 %s，每 %s 检查一次水龙头的余额", tu.Treasury().Hex(), *topUpInterval)
+		go tu.Run(ctx, *topUpInterval, func(t faucet.TopUp) {
+			log.Println(t)
+		})
+	}
+
+	st, err := openStore(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+	d := newDispatcher(st, f, *queueSize)
+	if err := d.resume(ctx); err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.run(ctx)
+	}()
+
+	srv := &server{
+		st:        st,
+		d:         d,
+		f:         f,
+		assets:    assets,
+		addrWait:  *addrCooldown,
+		ipWait:    *ipCooldown,
+		proxyHops: *proxyHops,
+	}
+	if *trustProxy && srv.proxyHops == 0 {
+		srv.proxyHops = 1
+	}
+	l, err := net.Listen("tcp", *listenAddr)
+	if err != nil {
+		return fmt.Errorf("监听 %s 失败：%s", *listenAddr, err)
+	}
+	hs := &http.Server{Handler: srv.routes(), ReadHeaderTimeout: time.Second * 10}
+	go hs.Serve(l)
+	log.Printf("水龙头已启动：http://%s", l.Addr())
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	<-sig
+	log.Println("正在退出，未完成的请求将在下次启动时继续处理")
+	shutdown, stop := context.WithTimeout(context.Background(), time.Second*5)
+	defer stop()
+	hs.Shutdown(shutdown)
+	cancel()
+	<-done
+	d.wait()
+	return nil
+}
+
+// loadNetwork 读取网络配置，指定了节点地址时代替配置中的地址
+func loadNetwork() (netconf.Profile, error) {
+	cfg, err := netconf.Load(*networksFile)
+	if err != nil {
+		return netconf.Profile{}, err
+	}
+	p, ok := cfg.Find(*networkName)
+	if !ok {
+		return p, fmt.Errorf("网络配置中没有 %s，可选：%s", *networkName, strings.Join(cfg.Names(), "、"))
+	}
+	if *rpcFlag != "" {
+		p.RPC = []string{*rpcFlag}
+	}
+	return p, nil
+}
+
+// parseAssets 根据参数生成可以领取的资产及单次上限，代币的上限按链上的小数位数换算
+func parseAssets(ctx context.Context, f *faucet.Faucet) ([]asset, error) {
+	var assets []asset
+	limit, err := units.ParseEther(*ethMax)
+	if err != nil {
+		return nil, fmt.Errorf("ETH 单次上限有误：%s", err)
+	}
+	if limit.Sign() > 0 {
+		assets = append(assets, asset{Name: assetETH, Max: limit, Decimals: units.Ether})
+	}
+	for _, s := range strings.Split(*tokenList, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		if !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("代币地址有误：%s", s)
+		}
+		token := common.HexToAddress(s)
+		decimals, err := f.Decimals(ctx, token)
+		if err != nil {
+			return nil, fmt.Errorf("代币 %s：%s", token.Hex(), err)
+		}
+		limit, err := units.ParseToken(*tokenMax, decimals)
+		if err != nil {
+			return nil, fmt.Errorf("代币单次上限有误：%s", err)
+		}
+		if limit.Sign() <= 0 {
+			return nil, fmt.Errorf("代币单次上限有误：%s", *tokenMax)
+		}
+		assets = append(assets, asset{Name: token.Hex(), Max: limit, Decimals: decimals})
+	}
+	if len(assets) == 0 {
+		return nil, errors.New("没有可以领取的资产，请设置 -eth-max 或 -tokens")
+	}
+	return assets, nil
+}
+
+// feeConfig 根据参数生成手续费设置，没有指定交易类型时使用网络的默认值
+func feeConfig(network netconf.Profile) (ethutil.FeeConfig, error) {
+	cfg := ethutil.FeeConfig{Mode: network.Fee}
+	var err error
+	if *feeMode != "" {
+		if cfg.Mode, err = ethutil.ParseFeeMode(*feeMode); err != nil {
+			return cfg, err
+		}
+	}
+	if cfg.MaxFeeCap, err = ethutil.ParseGwei(*maxFeeCap); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxTipCap, err = ethutil.ParseGwei(*maxTipCap); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// topUpRules 根据参数生成补充规则，代币规则按各代币的小数位数换算，用于所有可以领取的代币
+func topUpRules(assets []asset) ([]faucet.TopUpRule, error) {
+	parse := func(name, s string, a asset) (*big.Int, error) {
+		v, err := a.parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s有误：%s", name, err)
+		}
+		return v, nil
+	}
+	var rules []faucet.TopUpRule
+	eth := asset{Name: assetETH, Decimals: units.Ether}
+	threshold, err := parse("ETH 补充阈值", *ethThreshold, eth)
+	if err != nil {
+		return nil, err
+	}
+	target, err := parse("ETH 补充目标", *ethTarget, eth)
+	if err != nil {
+		return nil, err
+	}
+	rules = append(rules, faucet.TopUpRule{Threshold: threshold, Target: target})
+	if *tokenThreshold == "" {
+		return rules, nil
+	}
+	for _, a := range assets {
+		if a.Name == assetETH {
+			continue
+		}
+		if threshold, err = parse("代币补充阈值", *tokenThreshold, a); err != nil {
+			return nil, err
+		}
+		if target, err = parse("代币补充目标", *tokenTarget, a); err != nil {
+			return nil, err
+		}
+		rules = append(rules, faucet.TopUpRule{Token: common.HexToAddress(a.Name), Threshold: threshold, Target: target})
+	}
+	return rules, nil
+}
+
+// openSigner 按参数打开签名账户，密码与助记词先读环境变量，没有再在终端输入
+func openSigner(source, location, account, passEnv, mnemonicEnv string) (ethutil.Signer, error) {
+	kind, err := keys.ParseKind(source)
+	if err != nil {
+		return nil, err
+	}
+	spec := keys.Spec{Kind: kind, Location: location, Account: account}
+	secret := keys.Secret{Passphrase: os.Getenv(passEnv)}
+	if spec.Kind == keys.KindMnemonic {
+		secret.Mnemonic = os.Getenv(mnemonicEnv)
+		if strings.TrimSpace(secret.Mnemonic) == "" {
+			mnemonic, err := prompt.Stdin.PromptPassword(fmt.Sprintf("请输入 %s 的助记词：", spec))
+			if err != nil {
+				return nil, fmt.Errorf("读取助记词失败：%s", err)
+			}
+			secret.Mnemonic = mnemonic
+		}
+	} else if spec.NeedPassphrase() && secret.Passphrase == "" {
+		passphrase, err := prompt.Stdin.PromptPassword(fmt.Sprintf("请输入 %s 的密码：", spec))
+		if err != nil {
+			return nil, fmt.Errorf("读取密码失败：%s", err)
+		}
+		secret.Passphrase = passphrase
+	}
+	return keys.Open(spec, secret)
+}
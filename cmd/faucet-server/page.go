@@ -0,0 +1,94 @@
+package main
+
+// pageHTML 领取页面，只调用 /api 下的接口
+const pageHTML = `<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>测试网水龙头</title>
+<style>
+body { font-family: sans-serif; max-width: 560px; margin: 40px auto; padding: 0 16px; color: #222; }
+label { display: block; margin: 12px 0 4px; }
+input, select, button { width: 100%; box-sizing: border-box; padding: 8px; font-size: 14px; }
+button { margin-top: 16px; cursor: pointer; }
+#info { color: #666; font-size: 13px; word-break: break-all; }
+#result { margin-top: 16px; white-space: pre-wrap; word-break: break-all; }
+.error { color: #c00; }
+</style>
+</head>
+<body>
+<h2>测试网水龙头</h2>
+<div id="info">正在读取水龙头信息……</div>
+<form id="form">
+<label for="address">钱包地址</label>
+<input id="address" placeholder="0x..." required>
+<label for="asset">领取</label>
+<select id="asset"></select>
+<label for="amount">数量（如 0.05，留空领取上限）</label>
+<input id="amount" inputmode="decimal">
+<button id="submit" type="submit">领取</button>
+</form>
+<div id="result"></div>
+<script>
+const $ = id => document.getElementById(id);
+const statusText = {queued: "排队中", sending: "正在发送", sent: "已发送，等待确认", confirmed: "领取成功", failed: "领取失败"};
+
+function show(text, error) {
+  $("result").textContent = text;
+  $("result").className = error ? "error" : "";
+}
+
+fetch("/api/info").then(r => r.json()).then(info => {
+  $("info").textContent = info.network + "（链 ID " + info.chain_id + "），水龙头地址 " + info.faucet +
+    "；同一地址每 " + info.address_cooldown + " 秒、同一 IP 每 " + info.ip_cooldown + " 秒可领取一次";
+  for (const a of info.assets) {
+    const opt = document.createElement("option");
+    opt.value = a.asset;
+    opt.textContent = (a.asset === "ETH" ? info.symbol : a.asset) + "（单次最多 " + a.max + "）";
+    $("asset").appendChild(opt);
+  }
+}).catch(err => show("读取水龙头信息失败：" + err, true));
+
+function poll(id) {
+  fetch("/api/claims/" + id).then(r => r.json()).then(c => {
+    let text = "领取请求 " + c.id + "：" + (statusText[c.status] || c.status);
+    if (c.link || c.tx) text += "\n交易：" + (c.link || c.tx);
+    if (c.balance) text += "\n当前余额：" + c.balance;
+    if (c.error) text += "\n原因：" + c.error;
+    show(text, c.status === "failed");
+    if (c.status !== "confirmed" && c.status !== "failed") {
+      setTimeout(() => poll(id), 3000);
+    } else {
+      $("submit").disabled = false;
+    }
+  }).catch(err => {
+    show("查询领取结果失败：" + err, true);
+    $("submit").disabled = false;
+  });
+}
+
+$("form").addEventListener("submit", e => {
+  e.preventDefault();
+  $("submit").disabled = true;
+  show("正在提交……");
+  fetch("/api/claims", {
+    method: "POST",
+    headers: {"Content-Type": "application/json"},
+    body: JSON.stringify({address: $("address").value.trim(), asset: $("asset").value, amount: $("amount").value.trim()}),
+  }).then(r => r.json()).then(c => {
+    if (c.error && !c.id) {
+      show(c.error, true);
+      $("submit").disabled = false;
+      return;
+    }
+    poll(c.id);
+  }).catch(err => {
+    show("提交失败：" + err, true);
+    $("submit").disabled = false;
+  });
+});
+</script>
+</body>
+</html>
+`
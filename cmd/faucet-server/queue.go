@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/naiba/eth-tools/internal/faucet"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// dispatcher 只有一个协程签名广播，交易按排队顺序使用连续的 Nonce；
+// 广播后在后台并发等待确认，不阻塞后面的请求
+type dispatcher struct {
+	st    *store
+	f     *faucet.Faucet
+	queue chan uint64
+	wg    sync.WaitGroup
+}
+
+func newDispatcher(st *store, f *faucet.Faucet, size int) *dispatcher {
+	return &dispatcher{st: st, f: f, queue: make(chan uint64, size)}
+}
+
+// enqueue 把请求加入队列，队列已满时返回 false
+func (d *dispatcher) enqueue(id uint64) bool {
+	select {
+	case d.queue <- id:
+		return true
+	default:
+		return false
+	}
+}
+
+// pending 排队中的请求数
+func (d *dispatcher) pending() int {
+	return len(d.queue)
+}
+
+// run 依次发送队列中的请求，直到 ctx 取消。
+// 正在发送的请求不随 ctx 取消，发送完成后再退出，避免退出时不知道交易是否已经广播。
+func (d *dispatcher) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-d.queue:
+			sendCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			d.send(sendCtx, ctx, id)
+			cancel()
+		}
+	}
+}
+
+// wait 等待后台的确认协程退出
+func (d *dispatcher) wait() {
+	d.wg.Wait()
+}
+
+// request 领取请求对应的水龙头请求
+func (c *claim) request() (faucet.Request, error) {
+	req := faucet.Request{To: c.Address}
+	if !strings.EqualFold(c.Asset, assetETH) {
+		req.Token = common.HexToAddress(c.Asset)
+	}
+	amount, err := asset{Name: c.Asset, Decimals: c.Decimals}.parse(c.Amount)
+	if err != nil {
+		return req, &faucet.Error{Code: faucet.CodeInvalid, Err: fmt.Errorf("领取数量有误：%s", err)}
+	}
+	req.Amount = amount
+	return req, nil
+}
+
+// send 签名广播一笔领取交易，ctx 用于发送，确认在 confirmCtx 取消前一直等待
+func (d *dispatcher) send(ctx, confirmCtx context.Context, id uint64) {
+	c, err := d.st.update(id, func(c *claim) { c.Status = statusSending })
+	if err != nil {
+		log.Printf("领取请求 %d：%s", id, err)
+		return
+	}
+	req, err := c.request()
+	if err != nil {
+		d.fail(c, err)
+		return
+	}
+	tx, err := d.f.Send(ctx, req)
+	if err != nil {
+		d.fail(c, err)
+		return
+	}
+	if c, err = d.st.update(id, func(c *claim) {
+		c.Status = statusSent
+		c.Tx = tx.Hash().Hex()
+		c.Link = d.f.Network().TxURL(c.Tx)
+	}); err != nil {
+		log.Printf("领取请求 %d：%s", id, err)
+		return
+	}
+	log.Printf("领取请求 %d：已向 %s 发送 %s %s：Transaction-%s", c.ID, c.Address.Hex(), c.Amount, c.Asset, c.Tx)
+	d.wg.Add(1)
+	go d.confirm(confirmCtx, c, req, tx)
+}
+
+// confirm 等待交易确认并记录结果，服务退出时保持 sent 状态，重启后继续等待
+func (d *dispatcher) confirm(ctx context.Context, c *claim, req faucet.Request, tx *types.Transaction) {
+	defer d.wg.Done()
+	res, err := d.f.Confirm(ctx, req, tx)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		d.fail(c, err)
+		return
+	}
+	if _, err := d.st.update(c.ID, func(c *claim) {
+		c.Status = statusConfirmed
+		if res.Balance != nil {
+			c.Balance = units.Format(res.Balance, c.Decimals)
+		}
+	}); err != nil {
+		log.Printf("领取请求 %d：%s", c.ID, err)
+		return
+	}
+	log.Printf("领取请求 %d：已确认，区块 %d", c.ID, res.Block)
+}
+
+// fail 记录失败原因，代币确定没有发出时撤销冷却，用户可以立即重试
+func (d *dispatcher) fail(c *claim, err error) {
+	code := faucet.CodeUnconfirmed
+	var fe *faucet.Error
+	if errors.As(err, &fe) {
+		code = fe.Code
+	}
+	log.Printf("领取请求 %d 失败（%s）：%s", c.ID, code, err)
+	if _, err := d.st.update(c.ID, func(c *claim) {
+		c.Status = statusFailed
+		c.Code = string(code)
+		c.Error = err.Error()
+	}); err != nil {
+		log.Printf("领取请求 %d：%s", c.ID, err)
+	}
+	if code == faucet.CodeUnconfirmed {
+		return
+	}
+	if err := d.st.release(c); err != nil {
+		log.Printf("领取请求 %d 撤销冷却失败：%s", c.ID, err)
+	}
+}
+
+// resume 继续处理上次退出时未完成的请求：排队中的重新排队，已广播的继续等待确认，
+// 正在广播的无法判断是否已经发出，记为失败且保留冷却
+func (d *dispatcher) resume(ctx context.Context) error {
+	list, err := d.st.unfinished()
+	if err != nil {
+		return fmt.Errorf("读取未完成的领取请求失败：%s", err)
+	}
+	var queued []uint64
+	for _, c := range list {
+		switch c.Status {
+		case statusQueued:
+			queued = append(queued, c.ID)
+		case statusSending:
+			d.fail(c, &faucet.Error{Code: faucet.CodeUnconfirmed, Err: errors.New("服务在广播交易时退出，无法确认是否已经发出")})
+		case statusSent:
+			req, err := c.request()
+			if err != nil {
+				d.fail(c, err)
+				continue
+			}
+			hash := common.HexToHash(c.Tx)
+			tx, _, err := d.f.Client().TransactionByHash(ctx, hash)
+			if err != nil {
+				d.fail(c, &faucet.Error{Code: faucet.CodeUnconfirmed, Tx: hash, Err: fmt.Errorf("获取交易失败：%s", err)})
+				continue
+			}
+			d.wg.Add(1)
+			go d.confirm(ctx, c, req, tx)
+		}
+	}
+	if len(queued) > 0 {
+		log.Printf("继续处理 %d 个排队中的领取请求", len(queued))
+	}
+	// 积压的请求可能超过队列长度，在后台按顺序放入
+	go func() {
+		for _, id := range queued {
+			select {
+			case d.queue <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
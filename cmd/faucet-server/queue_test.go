@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/naiba/eth-tools/internal/faucet"
+	"github.com/naiba/eth-tools/internal/netconf"
+)
+
+// lostTxAPI 查不到任何交易的节点，如重启后交易已从交易池中丢失
+type lostTxAPI struct{}
+
+func (lostTxAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1337))
+}
+
+func (lostTxAPI) GetTransactionByHash(hash common.Hash) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func TestDispatcherResume(t *testing.T) {
+	path := t.TempDir()
+	st := openTestStore(t, path)
+	// 上次退出时各个状态的请求，队列只有 1 个位置，排队的请求要在后台按顺序放入
+	statuses := []claimStatus{statusQueued, statusSending, statusQueued, statusSent, statusConfirmed, statusFailed}
+	for i, status := range statuses {
+		c := testClaim(int64(i+1), "")
+		if _, err := st.reserve(c, time.Hour, 0); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := st.update(c.ID, func(c *claim) {
+			c.Status = status
+			if status == statusSent {
+				c.Tx = common.HexToHash("0x01").Hex()
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	st.Close()
+
+	st = openTestStore(t, path)
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("eth", lostTxAPI{}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f, err := faucet.New(ctx, rpc.DialInProc(srv), faucet.Config{Network: netconf.Profile{Name: "test", ChainID: 1337}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	d := newDispatcher(st, f, 1)
+	if err := d.resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []uint64{1, 3} {
+		select {
+		case id := <-d.queue:
+			if id != want {
+				t.Errorf("重新排队的请求为 %d，预期 %d", id, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("等待请求 %d 重新排队超时", want)
+		}
+	}
+
+	tests := []struct {
+		id     uint64
+		status claimStatus
+		code   string
+	}{
+		{1, statusQueued, ""},
+		// 无法确认是否发出，记为失败且保留冷却
+		{2, statusFailed, string(faucet.CodeUnconfirmed)},
+		{3, statusQueued, ""},
+		{4, statusFailed, string(faucet.CodeUnconfirmed)},
+		{5, statusConfirmed, ""},
+		{6, statusFailed, ""},
+	}
+	for _, tt := range tests {
+		c, err := st.get(tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Status != tt.status || c.Code != tt.code {
+			t.Errorf("请求 %d 为 %s（%s），预期 %s（%s）", tt.id, c.Status, c.Code, tt.status, tt.code)
+		}
+		if retry, _ := st.reserve(testClaim(int64(tt.id), "1.1.1.1"), time.Hour, 0); retry == 0 {
+			t.Errorf("请求 %d 的冷却被撤销", tt.id)
+		}
+	}
+}
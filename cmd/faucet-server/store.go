@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// claimStatus 领取请求的状态
+type claimStatus string
+
+const (
+	statusQueued    claimStatus = "queued"    // 排队中
+	statusSending   claimStatus = "sending"   // 正在签名广播，重启后无法判断是否已经发出
+	statusSent      claimStatus = "sent"      // 已广播，等待确认
+	statusConfirmed claimStatus = "confirmed" // 已确认
+	statusFailed    claimStatus = "failed"    // 失败
+)
+
+// claim 一次领取请求，保存在数据库中，重启后继续处理
+type claim struct {
+	ID       uint64         `json:"id"`
+	Address  common.Address `json:"address"`
+	Asset    string         `json:"asset"`    // ETH 或代币地址
+	Amount   string         `json:"amount"`   // 十进制数量，如 0.05
+	Decimals int            `json:"decimals"` // 资产的小数位数，换算最小单位时使用
+	IP       string         `json:"ip,omitempty"`
+	Status   claimStatus    `json:"status"`
+	Tx       string         `json:"tx,omitempty"`
+	Link     string         `json:"link,omitempty"`
+	Balance  string         `json:"balance,omitempty"` // 领取后的余额，与 Amount 单位相同
+	Code     string         `json:"code,omitempty"`
+	Error    string         `json:"error,omitempty"`
+	Created  time.Time      `json:"created"`
+	Updated  time.Time      `json:"updated"`
+}
+
+// cooldown 冷却记录，由 Claim 号的请求设置
+type cooldown struct {
+	Claim uint64    `json:"claim"`
+	Until time.Time `json:"until"`
+}
+
+// store 基于 LevelDB 的持久化状态：
+//
+//	claim/<编号>              领取请求
+//	cooldown/addr/<资产>/<地址> 钱包地址的冷却
+//	cooldown/ip/<资产>/<IP>   IP 的冷却
+//	seq                     最后一个领取请求的编号
+type store struct {
+	db *leveldb.DB
+
+	mu  sync.Mutex
+	seq uint64
+}
+
+func openStore(path string) (*store, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("打开数据库失败：%s", err)
+	}
+	s := &store{db: db}
+	data, err := db.Get([]byte("seq"), nil)
+	switch err {
+	case nil:
+		s.seq = binary.BigEndian.Uint64(data)
+	case leveldb.ErrNotFound:
+	default:
+		db.Close()
+		return nil, fmt.Errorf("读取数据库失败：%s", err)
+	}
+	return s, nil
+}
+
+func (s *store) Close() error {
+	return s.db.Close()
+}
+
+func claimKey(id uint64) []byte {
+	return []byte(fmt.Sprintf("claim/%020d", id))
+}
+
+func addrKey(asset string, addr common.Address) []byte {
+	return []byte("cooldown/addr/" + strings.ToLower(asset) + "/" + strings.ToLower(addr.Hex()))
+}
+
+func ipKey(asset, ip string) []byte {
+	return []byte("cooldown/ip/" + strings.ToLower(asset) + "/" + ip)
+}
+
+// cooldownOf 读取冷却记录，没有记录或已过期时返回 nil
+func (s *store) cooldownOf(key []byte, now time.Time) (*cooldown, error) {
+	data, err := s.db.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cd cooldown
+	if err := json.Unmarshal(data, &cd); err != nil {
+		return nil, err
+	}
+	if !now.Before(cd.Until) {
+		return nil, nil
+	}
+	return &cd, nil
+}
+
+// reserve 检查冷却并保存新的领取请求，同时设置地址与 IP 的冷却。
+// 仍在冷却中时返回需要等待的时间，不保存请求。
+func (s *store) reserve(c *claim, addrWait, ipWait time.Duration) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	keys := [][]byte{addrKey(c.Asset, c.Address), ipKey(c.Asset, c.IP)}
+	waits := []time.Duration{addrWait, ipWait}
+	var retry time.Duration
+	for i, key := range keys {
+		if waits[i] <= 0 {
+			continue
+		}
+		cd, err := s.cooldownOf(key, now)
+		if err != nil {
+			return 0, fmt.Errorf("读取冷却记录失败：%s", err)
+		}
+		if cd != nil && cd.Until.Sub(now) > retry {
+			retry = cd.Until.Sub(now)
+		}
+	}
+	if retry > 0 {
+		return retry, nil
+	}
+
+	c.ID = s.seq + 1
+	c.Status = statusQueued
+	c.Created, c.Updated = now, now
+	batch := new(leveldb.Batch)
+	data, err := json.Marshal(c)
+	if err != nil {
+		return 0, err
+	}
+	batch.Put(claimKey(c.ID), data)
+	for i, key := range keys {
+		if waits[i] <= 0 {
+			continue
+		}
+		data, err := json.Marshal(cooldown{Claim: c.ID, Until: now.Add(waits[i])})
+		if err != nil {
+			return 0, err
+		}
+		batch.Put(key, data)
+	}
+	seq := make([]byte, 8)
+	binary.BigEndian.PutUint64(seq, c.ID)
+	batch.Put([]byte("seq"), seq)
+	if err := s.db.Write(batch, nil); err != nil {
+		return 0, fmt.Errorf("保存领取请求失败：%s", err)
+	}
+	s.seq = c.ID
+	return 0, nil
+}
+
+// release 撤销请求设置的冷却，没有发出代币的请求不占用冷却时间
+func (s *store) release(c *claim) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	batch := new(leveldb.Batch)
+	for _, key := range [][]byte{addrKey(c.Asset, c.Address), ipKey(c.Asset, c.IP)} {
+		cd, err := s.cooldownOf(key, time.Now())
+		if err != nil {
+			return err
+		}
+		if cd != nil && cd.Claim == c.ID {
+			batch.Delete(key)
+		}
+	}
+	return s.db.Write(batch, nil)
+}
+
+// get 读取领取请求，不存在时返回 nil
+func (s *store) get(id uint64) (*claim, error) {
+	data, err := s.db.Get(claimKey(id), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var c claim
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// update 修改并保存领取请求
+func (s *store) update(id uint64, fn func(c *claim)) (*claim, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, fmt.Errorf("领取请求 %d 不存在", id)
+	}
+	fn(c)
+	c.Updated = time.Now()
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Put(claimKey(id), data, nil); err != nil {
+		return nil, fmt.Errorf("保存领取请求失败：%s", err)
+	}
+	return c, nil
+}
+
+// unfinished 按编号顺序返回所有未完成的领取请求
+func (s *store) unfinished() ([]*claim, error) {
+	it := s.db.NewIterator(util.BytesPrefix([]byte("claim/")), nil)
+	defer it.Release()
+	var list []*claim
+	for it.Next() {
+		var c claim
+		if err := json.Unmarshal(it.Value(), &c); err != nil {
+			return nil, err
+		}
+		if c.Status != statusConfirmed && c.Status != statusFailed {
+			list = append(list, &c)
+		}
+	}
+	return list, it.Error()
+}
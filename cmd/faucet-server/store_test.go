@@ -0,0 +1,117 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// openTestStore 在临时目录中打开数据库，测试结束时关闭
+func openTestStore(t *testing.T, path string) *store {
+	t.Helper()
+	st, err := openStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
+
+// testClaim 第 n 个钱包领取 ETH 的请求
+func testClaim(n int64, ip string) *claim {
+	return &claim{Address: common.BigToAddress(big.NewInt(n)), Asset: assetETH, Amount: "0.1", Decimals: 18, IP: ip}
+}
+
+func TestStoreReserve(t *testing.T) {
+	st := openTestStore(t, t.TempDir())
+	first := testClaim(1, "1.1.1.1")
+	if retry, err := st.reserve(first, time.Hour, time.Minute); err != nil || retry != 0 {
+		t.Fatalf("第一次领取：等待 %s，错误 %v", retry, err)
+	}
+	if first.ID != 1 || first.Status != statusQueued {
+		t.Fatalf("请求编号 %d，状态 %s，预期 1 与 %s", first.ID, first.Status, statusQueued)
+	}
+
+	tests := []struct {
+		name     string
+		c        *claim
+		addrWait time.Duration
+		ipWait   time.Duration
+		retry    time.Duration // 预期等待时间的上限，为 0 时预期保存成功
+	}{
+		{"同一地址", testClaim(1, "2.2.2.2"), time.Hour, time.Minute, time.Hour},
+		{"同一 IP", testClaim(2, "1.1.1.1"), time.Hour, time.Minute, time.Minute},
+		{"地址与 IP 都在冷却中时取较长的等待时间", testClaim(1, "1.1.1.1"), time.Hour, time.Minute, time.Hour},
+		{"地址冷却为 0 时不检查地址", testClaim(1, "3.3.3.3"), 0, time.Minute, 0},
+		{"IP 冷却为 0 时不检查 IP", testClaim(4, "1.1.1.1"), time.Hour, 0, 0},
+		{"其他资产不受影响", &claim{Address: first.Address, Asset: "0x0000000000000000000000000000000000000abc", Amount: "1", IP: "1.1.1.1"}, time.Hour, time.Minute, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retry, err := st.reserve(tt.c, tt.addrWait, tt.ipWait)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.retry == 0 {
+				if retry != 0 || tt.c.ID == 0 {
+					t.Errorf("等待 %s，请求编号 %d，预期保存成功", retry, tt.c.ID)
+				}
+				return
+			}
+			if retry <= tt.retry-time.Second || retry > tt.retry {
+				t.Errorf("等待 %s，预期 %s", retry, tt.retry)
+			}
+			if tt.c.ID != 0 {
+				t.Errorf("冷却中的请求被保存为 %d", tt.c.ID)
+			}
+		})
+	}
+}
+
+func TestStoreRelease(t *testing.T) {
+	path := t.TempDir()
+	st := openTestStore(t, path)
+	a := testClaim(1, "1.1.1.1")
+	if _, err := st.reserve(a, time.Hour, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	// IP 冷却为 0 时 b 只设置地址冷却
+	b := testClaim(2, "1.1.1.1")
+	if _, err := st.reserve(b, time.Hour, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.release(b); err != nil {
+		t.Fatal(err)
+	}
+	// b 不能撤销 a 设置的 IP 冷却
+	if retry, _ := st.reserve(testClaim(3, "1.1.1.1"), time.Hour, time.Hour); retry == 0 {
+		t.Error("其他请求撤销了 IP 冷却")
+	}
+	if retry, _ := st.reserve(testClaim(2, "2.2.2.2"), time.Hour, time.Hour); retry != 0 {
+		t.Errorf("撤销后地址仍需等待 %s", retry)
+	}
+
+	if err := st.release(a); err != nil {
+		t.Fatal(err)
+	}
+	c := testClaim(1, "1.1.1.1")
+	if retry, _ := st.reserve(c, time.Hour, time.Hour); retry != 0 {
+		t.Fatalf("撤销后仍需等待 %s", retry)
+	}
+
+	// 重启后编号继续递增，冷却仍然有效
+	st.Close()
+	st = openTestStore(t, path)
+	if retry, _ := st.reserve(testClaim(1, "9.9.9.9"), time.Hour, time.Hour); retry == 0 {
+		t.Error("重启后冷却失效")
+	}
+	d := testClaim(5, "5.5.5.5")
+	if _, err := st.reserve(d, time.Hour, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if d.ID != c.ID+1 {
+		t.Errorf("重启后的请求编号为 %d，预期 %d", d.ID, c.ID+1)
+	}
+}
@@ -0,0 +1,179 @@
+// stand-in-signer 本地替身签名服务，用于测试与演练远程签名：
+// 像 Clef / Web3Signer 一样通过 JSON-RPC 签名，只签白名单规则允许的交易。
+//
+//	STANDIN_PRIVATE_KEY=... stand-in-signer -http 127.0.0.1:8550 -chain-id 1337 -allow-to 0x... -max-value 0
+//
+// 签名工具中选择「远程签名」，地址填 http://127.0.0.1:8550 或 -ipc 指定的文件路径。
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/naiba/eth-tools/internal/keys"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+var (
+	httpAddr    = flag.String("http", "127.0.0.1:8550", "HTTP 监听地址，为空时不监听")
+	ipcPath     = flag.String("ipc", "", "IPC 文件路径，为空时不监听")
+	keySource   = flag.String("key-source", "env", "密钥来源：keystore、vault、mnemonic、env 或 file")
+	keyLoc      = flag.String("key-location", "STANDIN_PRIVATE_KEY", "密钥位置：keystore 或私钥文件路径、环境变量名、保管库文件、助记词派生路径")
+	keyAccount  = flag.String("key-account", "", "保管库中的密钥名称，或助记词派生的账户序号")
+	passEnv     = flag.String("passphrase-env", "STANDIN_PASSPHRASE", "读取密码的环境变量，为空时在终端输入")
+	mnemonicEnv = flag.String("mnemonic-env", "STANDIN_MNEMONIC", "读取助记词的环境变量，为空时在终端输入")
+	chainIDs    = flag.String("chain-id", "", "允许的链 ID，多个用逗号分隔，为空时不限制")
+	allowTo     = flag.String("allow-to", "", "允许的接收地址，多个用逗号分隔，为空时不限制")
+	methods     = flag.String("allow-method", "", "允许调用的合约方法，如 transfer(address,uint256) 或 0xa9059cbb，多个用分号分隔，为空时不限制")
+	maxValue    = flag.String("max-value", "", "单笔交易最多转出的原生币，不带单位时为 wei，也可以写成 0.5 ether，为空时不限制")
+	allowCreate = flag.Bool("allow-create", false, "允许部署合约")
+)
+
+func main() {
+	flag.Parse()
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	if *httpAddr == "" && *ipcPath == "" {
+		return errors.New("请至少指定 -http 或 -ipc")
+	}
+	rules, err := parseRules()
+	if err != nil {
+		return err
+	}
+	kind, err := keys.ParseKind(*keySource)
+	if err != nil {
+		return err
+	}
+	if kind == keys.KindRemote {
+		return errors.New("替身签名服务不能使用远程签名")
+	}
+	spec := keys.Spec{Kind: kind, Location: *keyLoc, Account: *keyAccount}
+	secret, err := readSecret(spec)
+	if err != nil {
+		return err
+	}
+	pk, err := keys.PrivateKey(spec, secret)
+	if err != nil {
+		return err
+	}
+	signer := keys.NewStandInSigner(pk, rules)
+	signer.OnSign = func(tx *types.Transaction, err error) {
+		to := "部署合约"
+		if tx.To() != nil {
+			to = tx.To().Hex()
+		}
+		if err != nil {
+			log.Printf("拒绝签名：nonce %d 接收 %s 金额 %s：%s", tx.Nonce(), to, tx.Value(), err)
+			return
+		}
+		log.Printf("已签名：nonce %d 接收 %s 金额 %s 交易 %s", tx.Nonce(), to, tx.Value(), tx.Hash().Hex())
+	}
+	srv, err := signer.Server()
+	if err != nil {
+		return err
+	}
+	defer srv.Stop()
+	if *httpAddr != "" {
+		l, err := net.Listen("tcp", *httpAddr)
+		if err != nil {
+			return fmt.Errorf("监听 %s 失败：%s", *httpAddr, err)
+		}
+		go http.Serve(l, srv)
+		log.Printf("签名账户 %s，HTTP 地址 http://%s", signer.Address().Hex(), l.Addr())
+	}
+	if *ipcPath != "" {
+		l, err := net.Listen("unix", *ipcPath)
+		if err != nil {
+			return fmt.Errorf("监听 %s 失败：%s", *ipcPath, err)
+		}
+		defer os.Remove(*ipcPath)
+		go srv.ServeListener(l)
+		log.Printf("签名账户 %s，IPC 文件 %s", signer.Address().Hex(), *ipcPath)
+	}
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	<-sig
+	return nil
+}
+
+func parseRules() (keys.SignRules, error) {
+	var rules keys.SignRules
+	rules.AllowCreate = *allowCreate
+	for _, s := range splitList(*chainIDs, ",") {
+		id, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || id == 0 {
+			return rules, fmt.Errorf("链 ID 有误：%s", s)
+		}
+		rules.ChainIDs = append(rules.ChainIDs, id)
+	}
+	for _, s := range splitList(*allowTo, ",") {
+		if !common.IsHexAddress(s) {
+			return rules, fmt.Errorf("地址有误：%s", s)
+		}
+		rules.To = append(rules.To, common.HexToAddress(s))
+	}
+	for _, s := range splitList(*methods, ";") {
+		sel, err := keys.ParseMethod(s)
+		if err != nil {
+			return rules, err
+		}
+		rules.Methods = append(rules.Methods, sel)
+	}
+	if *maxValue != "" {
+		v, err := units.ParseIn(*maxValue, "wei")
+		if err != nil {
+			return rules, fmt.Errorf("金额上限有误：%s", err)
+		}
+		rules.MaxValue = v
+	}
+	return rules, nil
+}
+
+func splitList(s, sep string) []string {
+	var list []string
+	for _, item := range strings.Split(s, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// readSecret 读取密码与助记词，先读环境变量，没有再在终端输入
+func readSecret(spec keys.Spec) (keys.Secret, error) {
+	secret := keys.Secret{Passphrase: os.Getenv(*passEnv)}
+	if spec.Kind == keys.KindMnemonic {
+		secret.Mnemonic = os.Getenv(*mnemonicEnv)
+		if strings.TrimSpace(secret.Mnemonic) == "" {
+			mnemonic, err := prompt.Stdin.PromptPassword("请输入助记词：")
+			if err != nil {
+				return secret, fmt.Errorf("读取助记词失败：%s", err)
+			}
+			secret.Mnemonic = mnemonic
+		}
+		return secret, nil
+	}
+	if spec.NeedPassphrase() && secret.Passphrase == "" {
+		passphrase, err := prompt.Stdin.PromptPassword(fmt.Sprintf("请输入 %s 的密码：", spec))
+		if err != nil {
+			return secret, fmt.Errorf("读取密码失败：%s", err)
+		}
+		secret.Passphrase = passphrase
+	}
+	return secret, nil
+}
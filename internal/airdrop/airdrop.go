@@ -0,0 +1,273 @@
+// Package airdrop 糖果分发引擎：按分发计划逐笔或通过 Disperse 合约批量转账，
+// 记录分发日志以便中断后续传，进度通过 Event 通道输出，界面、命令行和服务共用
+package airdrop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/naiba/eth-tools/internal/erc20"
+	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/netconf"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// Recipient 一个分发目标，对应钱包文件中的一行
+type Recipient struct {
+	Address common.Address
+	Amount  units.Amount // 为 0 时使用统一的分发数量
+	Memo    string
+	Line    int
+}
+
+// AmountOr 该钱包的分发数量，没有单独指定时为 def
+func (r Recipient) AmountOr(def units.Amount) units.Amount {
+	if r.Amount.Sign() > 0 {
+		return r.Amount
+	}
+	return def
+}
+
+func (r Recipient) String() string {
+	if r.Memo != "" {
+		return fmt.Sprintf("钱包-%s(%s)", r.Address.Hex(), r.Memo)
+	}
+	return "钱包-" + r.Address.Hex()
+}
+
+// Plan 一次分发的计划
+type Plan struct {
+	Recipients  []Recipient
+	Amount      units.Amount   // 统一的分发数量（个），钱包单独指定了数量的以钱包为准
+	Journal     string         // 分发日志文件，中断后据此续传；为空时不记录
+	Batch       bool           // 通过 Disperse 批量转账合约分发
+	Disperse    common.Address // 复用已部署的 Disperse 合约，零地址时部署新合约
+	ChunkSize   int            // 批量分发时每笔交易包含的钱包数，默认 100
+	Concurrency int            // 逐笔分发时同时等待打包的交易数，默认 8
+}
+
+// Validate 检查计划中每个钱包都有分发数量
+func (p *Plan) Validate() error {
+	if len(p.Recipients) == 0 {
+		return errors.New("请先导入目标钱包")
+	}
+	for _, target := range p.Recipients {
+		if target.AmountOr(p.Amount).Sign() <= 0 {
+			return fmt.Errorf("第 %d 行 %s 未指定分发数量", target.Line, target.Address.Hex())
+		}
+	}
+	return nil
+}
+
+// Config 执行分发所需的网络、账户与代币
+type Config struct {
+	Network netconf.Profile
+	Signer  ethutil.Signer
+	Token   common.Address
+	Fee     ethutil.FeeConfig
+	Watch   ethutil.WatcherOptions
+}
+
+// Executor 在一个节点连接上执行分发计划，进度从 Events 读取
+type Executor struct {
+	network netconf.Profile
+	client  ethutil.Backend
+	close   func() // 断开节点，为空时不需要断开
+	chainID *big.Int
+	fee     ethutil.FeeConfig
+	watcher *ethutil.Watcher
+	signer  ethutil.Signer
+	wallet  common.Address
+	token   common.Address
+	erc20   *erc20.Erc20
+	decimal uint8
+
+	mu     sync.Mutex
+	events chan Event
+	closed bool
+}
+
+// NewExecutor 核对节点的链 ID 并读取代币信息，rc 在 Close 时关闭
+func NewExecutor(ctx context.Context, rc *rpc.Client, cfg Config) (*Executor, error) {
+	client := ethclient.NewClient(rc)
+	ex, err := newExecutor(ctx, client, cfg)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	ex.watcher = ethutil.NewWatcher(rc, cfg.Watch)
+	ex.close = client.Close
+	if ex.watcher.Polling() {
+		ex.logf("节点不支持订阅新区块，改为定时查询交易状态")
+	}
+	return ex, nil
+}
+
+// NewBackendExecutor 在没有 RPC 连接的节点接口（如测试中的模拟链）上创建分发引擎，
+// 交易状态逐笔查询，Close 时不断开 client
+func NewBackendExecutor(ctx context.Context, client ethutil.Backend, cfg Config) (*Executor, error) {
+	ex, err := newExecutor(ctx, client, cfg)
+	if err != nil {
+		return nil, err
+	}
+	ex.watcher = ethutil.NewBackendWatcher(client, cfg.Watch)
+	return ex, nil
+}
+
+// newExecutor 核对链 ID 并读取代币信息，交易跟踪器由调用方创建
+func newExecutor(ctx context.Context, client ethutil.Backend, cfg Config) (*Executor, error) {
+	// 连接后先核对链 ID，之后每次签名前还会再核对
+	chainID := new(big.Int).SetUint64(cfg.Network.ChainID)
+	if err := ethutil.VerifyChainID(ctx, client, chainID); err != nil {
+		return nil, ChainError(cfg.Network, err)
+	}
+	wallet := cfg.Signer.Address()
+	token, err := erc20.NewErc20(cfg.Token, client)
+	if err != nil {
+		return nil, fmt.Errorf("代币错误：%s", err)
+	}
+	decimal, err := token.Decimals(&bind.CallOpts{From: wallet, Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("获取代币小数位数错误：%s", err)
+	}
+	return &Executor{
+		network: cfg.Network,
+		client:  client,
+		chainID: chainID,
+		fee:     cfg.Fee,
+		signer:  cfg.Signer,
+		wallet:  wallet,
+		token:   cfg.Token,
+		erc20:   token,
+		decimal: decimal,
+		events:  make(chan Event, 64),
+	}, nil
+}
+
+// Events 分发进度，调用方必须持续读取直到 Close 后通道关闭
+func (ex *Executor) Events() <-chan Event {
+	return ex.events
+}
+
+// Close 停止交易跟踪并断开节点，之后关闭 Events 通道
+func (ex *Executor) Close() {
+	ex.watcher.Close()
+	if ex.close != nil {
+		ex.close()
+	}
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	if !ex.closed {
+		ex.closed = true
+		close(ex.events)
+	}
+}
+
+// Client 执行分发使用的节点连接
+func (ex *Executor) Client() ethutil.Backend {
+	return ex.client
+}
+
+// Wallet 分发代币的钱包地址
+func (ex *Executor) Wallet() common.Address {
+	return ex.wallet
+}
+
+// Decimals 代币的小数位数
+func (ex *Executor) Decimals() uint8 {
+	return ex.decimal
+}
+
+// ChainError 链 ID 不符时给出醒目的警告
+func ChainError(network netconf.Profile, err error) error {
+	var mismatch *ethutil.ChainMismatchError
+	if errors.As(err, &mismatch) {
+		return fmt.Errorf("警告：%s 的节点不是预期的链，%s", network.Name, err)
+	}
+	return err
+}
+
+// TokenAmount 把代币数量换算成最小单位，小数位数超过代币的精度时返回错误
+func (ex *Executor) TokenAmount(a units.Amount) (*big.Int, error) {
+	return a.Raw(int(ex.decimal))
+}
+
+// validate 检查计划，并检查每个钱包的分发数量都能换算成代币的最小单位
+func (ex *Executor) validate(plan *Plan) error {
+	if err := plan.Validate(); err != nil {
+		return err
+	}
+	for _, target := range plan.Recipients {
+		if _, err := ex.TokenAmount(target.AmountOr(plan.Amount)); err != nil {
+			return fmt.Errorf("第 %d 行 %s 的分发数量有误：%s", target.Line, target.Address.Hex(), err)
+		}
+	}
+	return nil
+}
+
+// rawAmount 钱包的分发数量（最小单位），计划已经通过 validate 检查
+func (ex *Executor) rawAmount(plan *Plan, target Recipient) *big.Int {
+	v, _ := ex.TokenAmount(target.AmountOr(plan.Amount))
+	return v
+}
+
+// transactOpts 使用本次分发的链 ID 与手续费设置生成交易参数
+func (ex *Executor) transactOpts(ctx context.Context, o ethutil.TxOptions) (*bind.TransactOpts, error) {
+	o.ChainID = ex.chainID
+	o.Fee = ex.fee
+	opts, err := ethutil.NewTransactOpts(ctx, ex.client, ex.signer, o)
+	if err != nil {
+		return nil, ChainError(ex.network, err)
+	}
+	return opts, nil
+}
+
+// Result 一次分发的结果，按钱包计数
+type Result struct {
+	Total     int // 计划中的钱包数
+	Skipped   int // 根据分发日志跳过的钱包数
+	Confirmed int
+	Reverted  int
+	Dropped   int
+	Rejected  int            // 签名或广播失败
+	Unknown   int            // 超时或跟踪出错，结果未知，下次运行时按链上状态处理
+	Disperse  common.Address // 批量分发使用的合约
+}
+
+// Failed 本次没有分发成功的钱包数
+func (r *Result) Failed() int {
+	return r.Reverted + r.Dropped + r.Rejected + r.Unknown
+}
+
+func (r *Result) String() string {
+	return fmt.Sprintf("交易统计：确认 %d，执行失败 %d，被丢弃 %d，发送失败 %d，结果未知 %d", r.Confirmed, r.Reverted, r.Dropped, r.Rejected, r.Unknown)
+}
+
+// Run 执行分发计划：打开分发日志并按链上状态更新，跳过已完成的钱包，再逐笔或批量分发。
+// ctx 取消后停止发送与等待，已发送的交易在分发日志中保持已发送状态，下次运行时按链上状态处理。
+func (ex *Executor) Run(ctx context.Context, plan *Plan) (*Result, error) {
+	if err := ex.validate(plan); err != nil {
+		return nil, err
+	}
+	jn, todo, err := ex.resumeJournal(ctx, plan)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{Total: len(plan.Recipients), Skipped: len(plan.Recipients) - len(todo)}
+	if plan.Batch {
+		err = ex.batchDistribution(ctx, plan, jn, todo, res)
+	} else {
+		err = ex.pipelineDistribution(ctx, plan, jn, todo, res)
+	}
+	if cerr := jn.close(); cerr != nil && err == nil {
+		err = cerr
+	}
+	return res, err
+}
@@ -0,0 +1,582 @@
+package airdrop
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/keys"
+	"github.com/naiba/eth-tools/internal/merkle"
+	"github.com/naiba/eth-tools/internal/netconf"
+	"github.com/naiba/eth-tools/internal/testchain"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// testRun 模拟链上的一次分发：账户 0 部署测试代币（2 位小数）并执行分发
+type testRun struct {
+	chain     *testchain.Chain
+	token     *testchain.TestToken
+	tokenAddr common.Address
+	ex        *Executor
+
+	mu     sync.Mutex
+	events []Event
+	done   chan struct{}
+}
+
+// newTestRun 部署测试代币并给分发钱包增发 supply 个代币（最小单位）
+func newTestRun(t *testing.T, supply int64) *testRun {
+	t.Helper()
+	return newFilteredRun(t, supply, nil)
+}
+
+// sendFilter 决定分发引擎广播的第 n 笔（从 1 开始）交易如何处理：
+// forward 为 true 时发送到模拟链，再返回 err
+type sendFilter func(n int, tx *types.Transaction) (forward bool, err error)
+
+// filterBackend 按 sendFilter 拦截广播的模拟链
+type filterBackend struct {
+	ethutil.Backend
+	filter sendFilter
+
+	mu   sync.Mutex
+	sent int
+}
+
+func (b *filterBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	b.sent++
+	n := b.sent
+	b.mu.Unlock()
+	forward, err := b.filter(n, tx)
+	if forward {
+		if err := b.Backend.SendTransaction(ctx, tx); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// newFilteredRun 与 newTestRun 相同，filter 不为空时分发引擎的广播经过 filter
+func newFilteredRun(t *testing.T, supply int64, filter sendFilter) *testRun {
+	t.Helper()
+	c := testchain.New(t, 1)
+	tokenAddr, token := c.DeployToken(t, 0, false)
+	c.Mint(t, token, c.Address(0), supply)
+	c.AutoCommit(t, 20*time.Millisecond)
+
+	var backend ethutil.Backend = c
+	if filter != nil {
+		backend = &filterBackend{Backend: c, filter: filter}
+	}
+	ex, err := NewBackendExecutor(context.Background(), backend, Config{
+		Network: netconf.Profile{Name: "模拟链", ChainID: testchain.ChainID, Symbol: "ETH"},
+		Signer:  keys.NewKeySigner(c.Keys[0]),
+		Token:   tokenAddr,
+		Fee:     ethutil.FeeConfig{Mode: ethutil.FeeAuto},
+		Watch:   ethutil.WatcherOptions{Confirmations: 1, Timeout: 30 * time.Second, Interval: 20 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("创建分发引擎失败：%s", err)
+	}
+	r := &testRun{chain: c, token: token, tokenAddr: tokenAddr, ex: ex, done: make(chan struct{})}
+	go func() {
+		defer close(r.done)
+		for e := range ex.Events() {
+			r.mu.Lock()
+			r.events = append(r.events, e)
+			r.mu.Unlock()
+		}
+	}()
+	t.Cleanup(r.close)
+	return r
+}
+
+// close 关闭分发引擎并等待事件读完
+func (r *testRun) close() {
+	r.ex.Close()
+	<-r.done
+}
+
+// count 某类事件的数量
+func (r *testRun) count(kind EventKind) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int
+	for _, e := range r.events {
+		if e.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *testRun) balance(t *testing.T, addr common.Address) int64 {
+	t.Helper()
+	v, err := r.token.BalanceOf(&bind.CallOpts{}, addr)
+	if err != nil {
+		t.Fatalf("获取代币余额失败：%s", err)
+	}
+	return v.Int64()
+}
+
+// head 模拟链的最新区块号
+func (r *testRun) head(t *testing.T) uint64 {
+	t.Helper()
+	head, err := r.chain.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return head.Number.Uint64()
+}
+
+// gasUsed 分发钱包在 from 之后的区块中消耗的 Gas
+func (r *testRun) gasUsed(t *testing.T, from uint64) uint64 {
+	t.Helper()
+	ctx := context.Background()
+	var used uint64
+	for n := from + 1; n <= r.head(t); n++ {
+		block, err := r.chain.BlockByNumber(ctx, new(big.Int).SetUint64(n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tx := range block.Transactions() {
+			if from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err != nil || from != r.ex.Wallet() {
+				continue
+			}
+			rp, err := r.chain.TransactionReceipt(ctx, tx.Hash())
+			if err != nil {
+				t.Fatal(err)
+			}
+			used += rp.GasUsed
+		}
+	}
+	return used
+}
+
+func (r *testRun) run(t *testing.T, plan *Plan) *Result {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	res, err := r.ex.Run(ctx, plan)
+	if err != nil {
+		t.Fatalf("分发失败：%s", err)
+	}
+	return res
+}
+
+// recipients 生成 n 个目标钱包，第一个单独指定 2.5 个代币，其余使用计划的统一数量
+func recipients(from, n int) []Recipient {
+	var targets []Recipient
+	for i := 0; i < n; i++ {
+		target := Recipient{Address: common.BigToAddress(big.NewInt(int64(0x1000 + from + i))), Line: i + 1}
+		if i == 0 {
+			target.Amount, _ = units.ParseAmount("2.5")
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+func mustAmount(s string) units.Amount {
+	a, err := units.ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// checkJournal 分发日志中每个钱包都是 status
+func checkJournal(t *testing.T, r *testRun, path string, targets []Recipient, status journalStatus) {
+	t.Helper()
+	jn, err := openJournal(path, r.tokenAddr, r.ex.Wallet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, target := range targets {
+		if e := jn.entry(target.Address); e == nil || e.Status != status {
+			t.Errorf("分发日志中 %s 的记录为 %+v，预期 %s", target.Address.Hex(), e, status)
+		}
+	}
+}
+
+func TestRunOneByOne(t *testing.T) {
+	r := newTestRun(t, 100000)
+	targets := recipients(0, 5)
+	journal := filepath.Join(t.TempDir(), "wallets.txt.journal")
+	plan := &Plan{Recipients: targets, Amount: mustAmount("1.5"), Journal: journal, Concurrency: 2}
+	res := r.run(t, plan)
+	if res.Total != 5 || res.Confirmed != 5 || res.Failed() != 0 || res.Skipped != 0 {
+		t.Fatalf("分发结果：跳过 %d，%s，预期 5 笔全部确认", res.Skipped, res)
+	}
+	for i, target := range targets {
+		want := int64(150)
+		if i == 0 {
+			want = 250
+		}
+		if got := r.balance(t, target.Address); got != want {
+			t.Errorf("%s 的余额 %d，预期 %d", target.Address.Hex(), got, want)
+		}
+	}
+	if n := r.count(EventSubmitted); n != 5 {
+		t.Errorf("提交事件 %d 个，预期 5", n)
+	}
+	checkJournal(t, r, journal, targets, statusConfirmed)
+
+	// 再次运行时全部根据分发日志跳过，不重复分发
+	res = r.run(t, plan)
+	if res.Skipped != 5 || res.Confirmed != 0 {
+		t.Fatalf("再次分发结果：跳过 %d，%s，预期全部跳过", res.Skipped, res)
+	}
+	if got := r.balance(t, targets[1].Address); got != 150 {
+		t.Errorf("再次分发后余额 %d，预期 150", got)
+	}
+}
+
+func TestRunResume(t *testing.T) {
+	r := newTestRun(t, 100000)
+	targets := recipients(0, 4)
+	journal := filepath.Join(t.TempDir(), "wallets.txt.journal")
+	jn, err := openJournal(journal, r.tokenAddr, r.ex.Wallet())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 上次运行时 targets[1] 的交易已经广播，中断后才打包
+	tx, err := r.token.Transfer(r.chain.Transactor(t, 0), targets[1].Address, big.NewInt(150))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rp := r.chain.Mine(t, tx); rp.Status != 1 {
+		t.Fatal("转账失败")
+	}
+	nonce, err := r.chain.NonceAt(context.Background(), r.ex.Wallet(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for addr, e := range map[common.Address]journalEntry{
+		// 已确认的钱包不再分发（为了验证跳过，链上并没有这笔转账）
+		targets[0].Address: {Status: statusConfirmed, TxHash: common.HexToHash("0x01").Hex()},
+		targets[1].Address: {Status: statusSent, TxHash: tx.Hash().Hex(), Nonce: tx.Nonce()},
+		// 交易不在链上也不在交易池中，且 Nonce 已被占用，视为丢弃后重发
+		targets[2].Address: {Status: statusSent, TxHash: common.HexToHash("0x02").Hex(), Nonce: nonce - 1},
+	} {
+		e.Address = addr
+		if err := jn.update(addr, func(entry *journalEntry) { *entry = e }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := jn.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	res := r.run(t, &Plan{Recipients: targets, Amount: mustAmount("1.5"), Journal: journal})
+	if res.Skipped != 2 || res.Confirmed != 2 || res.Failed() != 0 {
+		t.Fatalf("续传结果：跳过 %d，%s，预期跳过 2 个、确认 2 个", res.Skipped, res)
+	}
+	for i, want := range []int64{0, 150, 150, 150} {
+		if got := r.balance(t, targets[i].Address); got != want {
+			t.Errorf("%s 的余额 %d，预期 %d", targets[i].Address.Hex(), got, want)
+		}
+	}
+	checkJournal(t, r, journal, targets, statusConfirmed)
+	jn, _ = openJournal(journal, r.tokenAddr, r.ex.Wallet())
+	if e := jn.entry(targets[1].Address); e.TxHash != tx.Hash().Hex() || e.Block == 0 {
+		t.Errorf("已发送的记录应当按回执更新：%+v", e)
+	}
+}
+
+func TestRunRebroadcastsUnsent(t *testing.T) {
+	r := newTestRun(t, 100000)
+	targets := recipients(0, 3)
+	journal := filepath.Join(t.TempDir(), "wallets.txt.journal")
+
+	// 上次运行时 targets[0] 的交易已经签名并记入日志，广播前程序崩溃
+	opts := r.chain.Transactor(t, 0)
+	opts.NoSend = true
+	tx, err := r.token.Transfer(opts, targets[0].Address, big.NewInt(250))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	jn, err := openJournal(journal, r.tokenAddr, r.ex.Wallet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := jn.update(targets[0].Address, func(e *journalEntry) {
+		e.Status, e.TxHash, e.Nonce, e.RawTx = statusSent, tx.Hash().Hex(), tx.Nonce(), hexutil.Encode(raw)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := jn.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	plan := &Plan{Recipients: targets, Amount: mustAmount("1.5"), Journal: journal}
+	res := r.run(t, plan)
+	if res.Skipped != 1 || res.Confirmed != 2 || res.Failed() != 0 {
+		t.Fatalf("续传结果：跳过 %d，%s，预期跳过 1 个、确认 2 个", res.Skipped, res)
+	}
+	// 重新广播的交易 Nonce 更小，本次的交易确认时它已经打包
+	for i, want := range []int64{250, 150, 150} {
+		if got := r.balance(t, targets[i].Address); got != want {
+			t.Errorf("%s 的余额 %d，预期 %d", targets[i].Address.Hex(), got, want)
+		}
+	}
+	r.mu.Lock()
+	var logged bool
+	for _, e := range r.events {
+		logged = logged || (e.Kind == EventLog && strings.Contains(e.Message, "已重新广播") && strings.Contains(e.Message, tx.Hash().Hex()))
+	}
+	r.mu.Unlock()
+	if !logged {
+		t.Error("没有输出重新广播的日志")
+	}
+
+	// 下次运行时按回执记为已确认，不再保留已签名交易
+	res = r.run(t, plan)
+	if res.Skipped != 3 {
+		t.Fatalf("再次分发结果：跳过 %d，预期全部跳过", res.Skipped)
+	}
+	checkJournal(t, r, journal, targets, statusConfirmed)
+	jn, _ = openJournal(journal, r.tokenAddr, r.ex.Wallet())
+	if e := jn.entry(targets[0].Address); e.RawTx != "" || e.Block == 0 {
+		t.Errorf("已确认的记录应当按回执更新并去掉已签名交易：%+v", e)
+	}
+}
+
+func TestRunStopsOnRejection(t *testing.T) {
+	// 第 3 笔转账被节点拒绝，没有进入交易池
+	var rejected uint64
+	r := newFilteredRun(t, 100000, func(n int, tx *types.Transaction) (bool, error) {
+		if n == 3 {
+			rejected = tx.Nonce()
+			return false, errors.New("insufficient funds for gas * price + value")
+		}
+		return true, nil
+	})
+	targets := recipients(0, 6)
+	journal := filepath.Join(t.TempDir(), "wallets.txt.journal")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	res, err := r.ex.Run(ctx, &Plan{Recipients: targets, Amount: mustAmount("1.5"), Journal: journal, Concurrency: 1})
+	if err == nil || !strings.Contains(err.Error(), "insufficient funds") {
+		t.Fatalf("分发错误为 %v，预期返回节点拒绝的原因", err)
+	}
+	if res.Confirmed != 2 || res.Rejected != 1 || res.Failed() != 1 {
+		t.Fatalf("分发结果：%s，预期确认 2 笔、发送失败 1 笔后停止", res)
+	}
+	for _, target := range targets[3:] {
+		if got := r.balance(t, target.Address); got != 0 {
+			t.Errorf("停止后 %s 仍收到 %d", target.Address.Hex(), got)
+		}
+	}
+	checkJournal(t, r, journal, targets[:2], statusConfirmed)
+	checkJournal(t, r, journal, targets[2:3], statusFailed)
+	// 被拒绝的 Nonce 已经归还，下一笔交易可以直接使用
+	if nonce, _ := r.chain.PendingNonceAt(ctx, r.ex.Wallet()); nonce != rejected {
+		t.Errorf("Nonce 为 %d，预期 %d", nonce, rejected)
+	}
+}
+
+func TestRunAbandonsMissingTx(t *testing.T) {
+	// 第 2 笔转账每次广播都被节点丢掉，重新广播也找不到
+	var (
+		mu      sync.Mutex
+		dropped *common.Hash
+	)
+	r := newFilteredRun(t, 100000, func(n int, tx *types.Transaction) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if n == 2 {
+			hash := tx.Hash()
+			dropped = &hash
+		}
+		return dropped == nil || *dropped != tx.Hash(), nil
+	})
+	targets := recipients(0, 4)
+	journal := filepath.Join(t.TempDir(), "wallets.txt.journal")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	res, err := r.ex.Run(ctx, &Plan{Recipients: targets, Amount: mustAmount("1.5"), Journal: journal, Concurrency: 1})
+	if err == nil || !strings.Contains(err.Error(), "放弃跟踪交易") {
+		t.Fatalf("分发错误为 %v，预期放弃跟踪后停止", err)
+	}
+	if res.Confirmed != 1 || res.Unknown != 1 {
+		t.Fatalf("分发结果：%s，预期确认 1 笔、结果未知 1 笔后停止", res)
+	}
+	if n := r.count(EventRebroadcast); n != 5 {
+		t.Errorf("重新广播 %d 次，预期 5 次", n)
+	}
+	// 放弃的交易仍可能被打包，保持已发送状态，下次运行时按链上状态处理
+	checkJournal(t, r, journal, targets[1:2], statusSent)
+	if got := r.balance(t, targets[2].Address); got != 0 {
+		t.Errorf("停止后 %s 仍收到 %d", targets[2].Address.Hex(), got)
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	r := newTestRun(t, 100000)
+	targets := recipients(0, 5)
+	journal := filepath.Join(t.TempDir(), "wallets.txt.journal")
+	plan := &Plan{Recipients: targets, Amount: mustAmount("1.5"), Journal: journal, Batch: true, ChunkSize: 2}
+	res := r.run(t, plan)
+	if res.Confirmed != 5 || res.Failed() != 0 || res.Disperse == (common.Address{}) {
+		t.Fatalf("批量分发结果：%s，合约 %s，预期 5 个钱包全部确认", res, res.Disperse.Hex())
+	}
+	if n := r.count(EventSubmitted); n != 3 {
+		t.Errorf("批量交易 %d 笔，预期 3 笔", n)
+	}
+	for i, target := range targets {
+		want := int64(150)
+		if i == 0 {
+			want = 250
+		}
+		if got := r.balance(t, target.Address); got != want {
+			t.Errorf("%s 的余额 %d，预期 %d", target.Address.Hex(), got, want)
+		}
+	}
+	checkJournal(t, r, journal, targets, statusConfirmed)
+
+	// 复用已部署的合约，并且上次授权的额度已经用完
+	more := recipients(10, 3)
+	res2 := r.run(t, &Plan{Recipients: more, Amount: mustAmount("1"), Batch: true, Disperse: res.Disperse})
+	if res2.Confirmed != 3 || res2.Disperse != res.Disperse {
+		t.Fatalf("复用合约的分发结果：%s，合约 %s", res2, res2.Disperse.Hex())
+	}
+	if got := r.balance(t, more[2].Address); got != 100 {
+		t.Errorf("余额 %d，预期 100", got)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	r := newTestRun(t, 500)
+	targets := recipients(0, 4)
+	// 测试代币拒绝转给 0 地址
+	targets[3].Address = common.Address{}
+	nonce, err := r.chain.PendingNonceAt(context.Background(), r.ex.Wallet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := r.ex.DryRun(context.Background(), &Plan{Recipients: targets, Amount: mustAmount("1.5")})
+	if err != nil {
+		t.Fatalf("预演失败：%s", err)
+	}
+	if report.Count != 4 || report.TokensRaw.Int64() != 700 || report.Tokens.String() != "7" {
+		t.Errorf("预演统计 %d 个钱包、%s 个代币，预期 4 个钱包、7 个代币", report.Count, report.Tokens)
+	}
+	if len(report.Failures) != 1 {
+		t.Errorf("预计失败 %q，预期只有 0 地址失败", report.Failures)
+	}
+	if !report.TokenShort() || report.FeeShort() || report.Gas.Sign() <= 0 {
+		t.Errorf("余额检查错误：代币不足 %v，手续费不足 %v，Gas %s", report.TokenShort(), report.FeeShort(), report.Gas)
+	}
+	if report.Err() == nil {
+		t.Error("代币不足时预演应当不通过")
+	}
+	if after, _ := r.chain.PendingNonceAt(context.Background(), r.ex.Wallet()); after != nonce {
+		t.Errorf("预演不应发送交易，Nonce 从 %d 变为 %d", nonce, after)
+	}
+}
+
+func TestDryRunBatch(t *testing.T) {
+	r := newTestRun(t, 100000)
+	ctx := context.Background()
+	targets := recipients(0, 5)
+	plan := &Plan{Recipients: targets, Amount: mustAmount("1.5"), Batch: true, ChunkSize: 2}
+	report, err := r.ex.DryRun(ctx, plan)
+	if err != nil {
+		t.Fatalf("预演失败：%s", err)
+	}
+	// 部署合约、授权，再分 3 批
+	if report.Txs != 5 || report.Err() != nil {
+		t.Fatalf("预演 %d 笔交易，结果 %v，预期 5 笔并通过", report.Txs, report.Err())
+	}
+	start := r.head(t)
+	res := r.run(t, plan)
+	used := r.gasUsed(t, start)
+	if report.Gas.Uint64() < used || report.Gas.Uint64() > used*3/2 {
+		t.Errorf("预计 Gas %s，实际 %d", report.Gas, used)
+	}
+
+	// 复用合约且额度足够时直接估算每批交易
+	more := recipients(10, 3)
+	tx, err := r.token.Approve(r.chain.Transactor(t, 0), res.Disperse, big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.chain.Mine(t, tx)
+	plan = &Plan{Recipients: more, Amount: mustAmount("1"), Batch: true, Disperse: res.Disperse, ChunkSize: 2}
+	if report, err = r.ex.DryRun(ctx, plan); err != nil {
+		t.Fatalf("预演失败：%s", err)
+	}
+	if report.Txs != 2 || report.Err() != nil {
+		t.Fatalf("预演 %d 笔交易，结果 %v，预期 2 笔并通过", report.Txs, report.Err())
+	}
+	start = r.head(t)
+	r.run(t, plan)
+	if used := r.gasUsed(t, start); report.Gas.Uint64() < used || report.Gas.Uint64() > used*3/2 {
+		t.Errorf("预计 Gas %s，实际 %d", report.Gas, used)
+	}
+}
+
+func TestDeployMerkle(t *testing.T) {
+	r := newTestRun(t, 100000)
+	targets := recipients(0, 3)
+	// 同一地址出现两次时数量累加
+	targets = append(targets, Recipient{Address: targets[1].Address, Line: 4})
+	plan := &Plan{Recipients: targets, Amount: mustAmount("1.5")}
+	tree, err := r.ex.MerkleTree(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.Total().Int64() != 700 {
+		t.Fatalf("领取总量 %s，预期 700", tree.Total())
+	}
+	addr, err := r.ex.DeployMerkle(context.Background(), tree)
+	if err != nil {
+		t.Fatalf("部署领取合约失败：%s", err)
+	}
+	if got := r.balance(t, addr); got != 700 {
+		t.Fatalf("领取合约的代币余额 %d，预期 700", got)
+	}
+	distributor, err := merkle.NewMerkleDistributor(addr, r.chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root, _ := distributor.MerkleRoot(&bind.CallOpts{}); root != tree.Root() {
+		t.Fatalf("合约中的树根 %x，预期 %s", root, tree.Root().Hex())
+	}
+	if owner, _ := distributor.Owner(&bind.CallOpts{}); owner != r.ex.Wallet() {
+		t.Fatalf("合约的 owner 为 %s，预期分发钱包", owner.Hex())
+	}
+
+	// 任何人都可以按证明替目标钱包领取
+	account := targets[1].Address
+	amount, proof, _ := tree.Proof(account)
+	tx, err := distributor.Claim(r.chain.Transactor(t, 0), account, amount, merkle.ProofArgs(proof))
+	if err != nil {
+		t.Fatalf("领取失败：%s", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if rp, err := bind.WaitMined(ctx, r.chain, tx); err != nil || rp.Status != 1 {
+		t.Fatalf("领取交易失败：%v", err)
+	}
+	if got := r.balance(t, account); got != 300 {
+		t.Errorf("领取后余额 %d，预期 300", got)
+	}
+}
@@ -0,0 +1,223 @@
+package airdrop
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/naiba/eth-tools/internal/disperse"
+	"github.com/naiba/eth-tools/internal/ethutil"
+)
+
+// batchDistribution 授权 Disperse 合约后按批次分发，结果计入 res
+func (ex *Executor) batchDistribution(ctx context.Context, plan *Plan, jn *journal, todo []Recipient, res *Result) error {
+	res.Disperse = plan.Disperse
+	if len(todo) == 0 {
+		return nil
+	}
+	contractAddr, contract, err := ex.prepareDisperse(ctx, plan.Disperse)
+	if err != nil {
+		return err
+	}
+	res.Disperse = contractAddr
+	total := new(big.Int)
+	for _, target := range todo {
+		total.Add(total, ex.rawAmount(plan, target))
+	}
+	if err := ex.approveDisperse(ctx, contractAddr, total); err != nil {
+		return err
+	}
+
+	size := plan.ChunkSize
+	if size < 1 {
+		size = 100
+	}
+	for start := 0; start < len(todo); start += size {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		end := start + size
+		if end > len(todo) {
+			end = len(todo)
+		}
+		chunk := todo[start:end]
+		subject := Event{Batch: start/size + 1, Count: len(chunk)}
+		addrs := make([]common.Address, len(chunk))
+		values := make([]*big.Int, len(chunk))
+		for i, target := range chunk {
+			addrs[i] = target.Address
+			values[i] = ex.rawAmount(plan, target)
+		}
+
+		opts, err := ex.transactOpts(ctx, ethutil.TxOptions{})
+		if err != nil {
+			return err
+		}
+		signer := opts.Signer
+		opts.Signer = func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			signed, err := signer(addr, tx)
+			if err != nil {
+				return nil, err
+			}
+			raw, err := signed.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			// 广播前记录交易哈希与已签名交易，中断后据此判断是否已经发送，没有发送时重新广播；
+			// 已签名交易只记录在第一个钱包上
+			if err := jn.updateMany(addrs, func(e *journalEntry) {
+				e.Status = statusSent
+				e.TxHash = signed.Hash().Hex()
+				e.Nonce = signed.Nonce()
+				e.Error = ""
+				if e.Address == addrs[0] {
+					e.RawTx = hexutil.Encode(raw)
+				}
+			}); err != nil {
+				return nil, err
+			}
+			return signed, nil
+		}
+		tx, err := contract.DisperseToken(opts, ex.token, addrs, values)
+		if err != nil {
+			res.Rejected += len(chunk)
+			subject.Kind, subject.Err = EventFailed, err
+			ex.emit(subject)
+			rejected := ethutil.IsRejected(err)
+			// 已签名的交易可能已经广播出去，保持 sent 状态，下次运行时按链上状态处理；
+			// 被节点明确拒绝的交易没有发送
+			if err := jn.updateMany(addrs, func(e *journalEntry) {
+				if e.Status != statusSent || rejected {
+					e.Status = statusFailed
+				}
+				e.Error = err.Error()
+			}); err != nil {
+				return err
+			}
+			if rejected {
+				// 之后的批次也会被拒绝
+				return fmt.Errorf("交易被节点拒绝，停止分发：%s", err)
+			}
+			continue
+		}
+		subject.Tx, subject.Nonce = tx.Hash(), tx.Nonce()
+		submitted := subject
+		submitted.Kind = EventSubmitted
+		ex.emit(submitted)
+		txRes, err := ex.wait(ctx, tx, subject)
+		if err != nil {
+			res.Unknown += len(chunk)
+			subject.Kind, subject.Err = EventUnknown, err
+			ex.emit(subject)
+			continue
+		}
+		if txRes.Status == ethutil.TxTimeout || txRes.Status == ethutil.TxAbandoned {
+			// 保持 sent 状态，下次运行时按链上状态处理
+			res.Unknown += len(chunk)
+			subject.Kind = EventTimeout
+			if txRes.Status == ethutil.TxAbandoned {
+				subject.Kind, subject.Err = EventUnknown, txRes.Err
+			}
+			ex.emit(subject)
+			continue
+		}
+		rp := txRes.Receipt
+		status, errMsg := statusConfirmed, ""
+		switch txRes.Status {
+		case ethutil.TxSuccess:
+			res.Confirmed += len(chunk)
+			subject.Kind = EventConfirmed
+		case ethutil.TxReverted:
+			res.Reverted += len(chunk)
+			subject.Kind, subject.Reason = EventReverted, ex.revertReason(ctx, tx, rp)
+			status, errMsg = statusFailed, "交易执行失败："+subject.Reason
+		case ethutil.TxDropped:
+			res.Dropped += len(chunk)
+			subject.Kind = EventDropped
+			status, errMsg = statusFailed, "交易已被丢弃"
+		}
+		if rp != nil {
+			subject.Block, subject.GasUsed = rp.BlockNumber.Uint64(), rp.GasUsed
+		}
+		ex.emit(subject)
+		if err := jn.updateMany(addrs, func(e *journalEntry) {
+			e.Status = status
+			e.Error = errMsg
+			if rp != nil {
+				e.Block = rp.BlockNumber.Uint64()
+				e.GasUsed = rp.GasUsed
+			}
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepareDisperse 复用指定的 Disperse 合约，未指定时部署一个新的
+func (ex *Executor) prepareDisperse(ctx context.Context, addr common.Address) (common.Address, *disperse.Disperse, error) {
+	if addr != (common.Address{}) {
+		code, err := ex.client.CodeAt(ctx, addr, nil)
+		if err != nil {
+			return addr, nil, fmt.Errorf("获取批量合约代码失败：%s", err)
+		}
+		if len(code) == 0 {
+			return addr, nil, fmt.Errorf("地址 %s 上没有合约", addr.Hex())
+		}
+		contract, err := disperse.NewDisperse(addr, ex.client)
+		if err != nil {
+			return addr, nil, fmt.Errorf("批量合约错误：%s", err)
+		}
+		ex.logf("复用批量合约：%s", addr.Hex())
+		return addr, contract, nil
+	}
+	ex.logf("正在部署批量合约")
+	opts, err := ex.transactOpts(ctx, ethutil.TxOptions{})
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("部署批量合约失败：%s", err)
+	}
+	addr, tx, contract, err := disperse.DeployDisperse(opts, ex.client)
+	if err != nil {
+		return addr, nil, fmt.Errorf("部署批量合约失败：%s", err)
+	}
+	if err := ex.waitSuccess(ctx, tx, "部署批量合约"); err != nil {
+		return addr, nil, fmt.Errorf("部署批量合约失败：%s", err)
+	}
+	ex.logf("批量合约已部署：%s，之后可以直接复用", addr.Hex())
+	return addr, contract, nil
+}
+
+// approveDisperse 确保批量合约可以转走 total 个代币
+func (ex *Executor) approveDisperse(ctx context.Context, spender common.Address, total *big.Int) error {
+	allowance, err := ex.erc20.Allowance(&bind.CallOpts{From: ex.wallet, Context: ctx}, ex.wallet, spender)
+	if err != nil {
+		return fmt.Errorf("获取授权额度失败：%s", err)
+	}
+	if allowance.Cmp(total) >= 0 {
+		return nil
+	}
+	// 部分代币要求先把授权额度清零才能修改
+	values := []*big.Int{total}
+	if allowance.Sign() > 0 {
+		values = []*big.Int{big.NewInt(0), total}
+	}
+	for _, value := range values {
+		opts, err := ex.transactOpts(ctx, ethutil.TxOptions{})
+		if err != nil {
+			return fmt.Errorf("授权批量合约失败：%s", err)
+		}
+		tx, err := ex.erc20.Approve(opts, spender, value)
+		if err != nil {
+			return fmt.Errorf("授权批量合约失败：%s", err)
+		}
+		ex.logf("正在授权批量合约使用 %s 个代币（最小单位）：Transaction-%s", value, tx.Hash().String())
+		if err := ex.waitSuccess(ctx, tx, "授权批量合约"); err != nil {
+			return fmt.Errorf("授权批量合约失败：%s", err)
+		}
+	}
+	return nil
+}
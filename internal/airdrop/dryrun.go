@@ -0,0 +1,237 @@
+package airdrop
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/naiba/eth-tools/internal/disperse"
+	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// DryRunReport 预演的结果
+type DryRunReport struct {
+	Count        int          // 待分发的钱包数
+	Skipped      int          // 根据分发日志跳过的钱包数
+	Tokens       units.Amount // 代币总量（个）
+	TokensRaw    *big.Int     // 代币总量（最小单位）
+	TokenBalance *big.Int     // 钱包的代币余额（最小单位）
+	Txs          int          // 预计发送的交易数，批量分发时包括部署与授权批量合约
+	Gas          *big.Int     // 预计消耗的 Gas
+	Fees         *ethutil.Fees
+	MaxFee       *big.Int // 按最高价格计算的手续费（wei）
+	Balance      *big.Int // 钱包的原生币余额（wei）
+	Failures     []string // 预计失败的钱包及原因
+}
+
+// TokenShort 代币余额是否不足
+func (r *DryRunReport) TokenShort() bool {
+	return r.TokenBalance.Cmp(r.TokensRaw) < 0
+}
+
+// FeeShort 原生币余额是否不足以支付手续费
+func (r *DryRunReport) FeeShort() bool {
+	return r.Balance.Cmp(r.MaxFee) < 0
+}
+
+// Err 预演未通过时返回原因
+func (r *DryRunReport) Err() error {
+	var problems int
+	if r.TokenShort() {
+		problems++
+	}
+	if r.FeeShort() {
+		problems++
+	}
+	if problems > 0 || len(r.Failures) > 0 {
+		return fmt.Errorf("预演未通过：%d 项余额问题，%d 个钱包预计失败", problems, len(r.Failures))
+	}
+	return nil
+}
+
+// DryRun 预演分发计划：检查余额并逐笔估算 Gas，不广播任何交易。
+// 批量分发时按部署合约、授权与每批一笔 disperseToken 估算，见 estimateBatch
+func (ex *Executor) DryRun(ctx context.Context, plan *Plan) (*DryRunReport, error) {
+	if err := ex.validate(plan); err != nil {
+		return nil, err
+	}
+	fees, err := ethutil.SuggestFees(ctx, ex.client, ex.fee)
+	if err != nil {
+		return nil, err
+	}
+	jn, err := openJournal(plan.Journal, ex.token, ex.wallet)
+	if err != nil {
+		return nil, err
+	}
+	r := &DryRunReport{
+		TokensRaw: new(big.Int),
+		Gas:       new(big.Int),
+		Fees:      fees,
+	}
+	var todo []Recipient
+	var gases []uint64 // todo 中每个钱包单独转账的 Gas，预计失败时为 0
+	for _, target := range plan.Recipients {
+		if e := jn.entry(target.Address); e != nil && (e.Status == statusConfirmed || e.Status == statusSent) {
+			r.Skipped++
+			continue
+		}
+		r.Count++
+		todo = append(todo, target)
+		gases = append(gases, 0)
+		bnAmount := ex.rawAmount(plan, target)
+		r.Tokens = r.Tokens.Add(target.AmountOr(plan.Amount))
+		r.TokensRaw.Add(r.TokensRaw, bnAmount)
+
+		data, err := revertABIs[0].Pack("transfer", target.Address, bnAmount)
+		if err != nil {
+			return nil, err
+		}
+		msg := ethereum.CallMsg{From: ex.wallet, To: &ex.token, Data: data}
+		gas, err := ex.client.EstimateGas(ctx, msg)
+		if err != nil {
+			if data, ok := ethutil.RevertData(err); ok {
+				err = fmt.Errorf("%s，失败原因：%s", err, ethutil.DecodeRevert(data, revertABIs...))
+			}
+			r.Failures = append(r.Failures, fmt.Sprintf("%s,第 %d 行：%s", target, target.Line, err))
+			continue
+		}
+		gases[len(gases)-1] = gas
+		out, err := ex.client.CallContract(ctx, msg, nil)
+		if err != nil {
+			r.Failures = append(r.Failures, fmt.Sprintf("%s,第 %d 行：%s", target, target.Line, err))
+			continue
+		}
+		var success bool
+		if err := revertABIs[0].UnpackIntoInterface(&success, "transfer", out); err == nil && !success {
+			r.Failures = append(r.Failures, fmt.Sprintf("%s,第 %d 行：transfer 返回 false", target, target.Line))
+		}
+	}
+
+	if plan.Batch {
+		if err := ex.estimateBatch(ctx, plan, todo, gases, r); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, gas := range gases {
+			if gas > 0 {
+				r.Txs++
+				r.Gas.Add(r.Gas, new(big.Int).SetUint64(gas))
+			}
+		}
+	}
+
+	if r.TokenBalance, err = ex.erc20.BalanceOf(&bind.CallOpts{From: ex.wallet, Context: ctx}, ex.wallet); err != nil {
+		return nil, fmt.Errorf("获取代币余额失败：%s", err)
+	}
+	if r.Balance, err = ex.client.BalanceAt(ctx, ex.wallet, nil); err != nil {
+		return nil, fmt.Errorf("获取 %s 余额失败：%s", ex.network.Symbol, err)
+	}
+	r.MaxFee = new(big.Int).Mul(r.Gas, fees.MaxPrice())
+	return r, nil
+}
+
+// estimateBatch 估算批量分发的交易数与 Gas：没有指定合约时部署一个，授权额度不足时授权（已有额度时先清零），
+// 之后每 ChunkSize 个钱包一笔 disperseToken。合约已部署且额度足够时直接估算每批的 Gas；
+// 否则 disperseToken 会因为没有授权而失败，按合约的执行过程近似：转入合约一次，再逐个转出
+func (ex *Executor) estimateBatch(ctx context.Context, plan *Plan, todo []Recipient, gases []uint64, r *DryRunReport) error {
+	if len(todo) == 0 {
+		return nil
+	}
+	addGas := func(gas uint64) {
+		r.Txs++
+		r.Gas.Add(r.Gas, new(big.Int).SetUint64(gas))
+	}
+	spender := plan.Disperse
+	deploy := spender == (common.Address{})
+	if deploy {
+		gas, err := ex.client.EstimateGas(ctx, ethereum.CallMsg{From: ex.wallet, Data: common.FromHex(disperse.DisperseBin)})
+		if err != nil {
+			return fmt.Errorf("估算部署批量合约的 Gas 失败：%s", err)
+		}
+		addGas(gas)
+		// 按部署后的合约地址估算授权
+		nonce, err := ex.client.PendingNonceAt(ctx, ex.wallet)
+		if err != nil {
+			return fmt.Errorf("获取钱包 Nonce 失败：%s", err)
+		}
+		spender = crypto.CreateAddress(ex.wallet, nonce)
+	}
+
+	allowance, err := ex.erc20.Allowance(&bind.CallOpts{From: ex.wallet, Context: ctx}, ex.wallet, spender)
+	if err != nil {
+		return fmt.Errorf("获取授权额度失败：%s", err)
+	}
+	approved := allowance.Cmp(r.TokensRaw) >= 0
+	if !approved {
+		data, err := revertABIs[0].Pack("approve", spender, r.TokensRaw)
+		if err != nil {
+			return err
+		}
+		gas, err := ex.client.EstimateGas(ctx, ethereum.CallMsg{From: ex.wallet, To: &ex.token, Data: data})
+		if err != nil {
+			return fmt.Errorf("估算授权批量合约的 Gas 失败：%s", err)
+		}
+		addGas(gas)
+		if allowance.Sign() > 0 {
+			addGas(gas)
+		}
+	}
+
+	size := plan.ChunkSize
+	if size < 1 {
+		size = 100
+	}
+	for start := 0; start < len(todo); start += size {
+		end := start + size
+		if end > len(todo) {
+			end = len(todo)
+		}
+		if !deploy && approved {
+			addrs := make([]common.Address, end-start)
+			values := make([]*big.Int, end-start)
+			for i, target := range todo[start:end] {
+				addrs[i], values[i] = target.Address, ex.rawAmount(plan, target)
+			}
+			data, err := revertABIs[1].Pack("disperseToken", ex.token, addrs, values)
+			if err != nil {
+				return err
+			}
+			gas, err := ex.client.EstimateGas(ctx, ethereum.CallMsg{From: ex.wallet, To: &spender, Data: data})
+			if err == nil {
+				addGas(gas)
+				continue
+			}
+			r.Failures = append(r.Failures, fmt.Sprintf("第 %d 批：%s", start/size+1, err))
+		}
+		addGas(approxChunkGas(gases[start:end]))
+	}
+	return nil
+}
+
+// approxChunkGas 按单独转账的 Gas 近似一批 disperseToken 的 Gas：一笔交易的基础费用，
+// 一次 transferFrom 把代币转入合约（按一次转账另加更新授权额度计），再逐个转出；
+// 单独转账的 Gas 包含了 calldata，大致抵消批量交易中每个钱包的地址与数量
+func approxChunkGas(gases []uint64) uint64 {
+	const (
+		txGas        = 21000
+		allowanceGas = 5000
+	)
+	var total, max uint64
+	for _, gas := range gases {
+		if gas > txGas {
+			total += gas - txGas
+		}
+		if gas > max {
+			max = gas
+		}
+	}
+	if max > txGas {
+		total += max - txGas + allowanceGas
+	}
+	return txGas + total
+}
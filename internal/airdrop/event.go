@@ -0,0 +1,113 @@
+package airdrop
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// EventKind 分发进度的类型
+type EventKind int
+
+const (
+	EventLog         EventKind = iota // 一般进度信息，见 Message
+	EventSkipped                      // 根据分发日志跳过的钱包，Reason 为原因
+	EventSubmitted                    // 交易已签名广播
+	EventFailed                       // 签名或广播失败，见 Err
+	EventMined                        // 交易已打包，等待确认
+	EventReorged                      // 区块重组，交易回到交易池
+	EventRebroadcast                  // 交易不在交易池中，已重新广播，失败时见 Err
+	EventTrackError                   // 查询交易状态时出错，会继续重试
+	EventConfirmed                    // 交易达到确认数且执行成功
+	EventReverted                     // 交易执行失败，Reason 为失败原因
+	EventDropped                      // 交易已被丢弃
+	EventTimeout                      // 等待确认超时，结果未知
+	EventUnknown                      // 跟踪交易失败，结果未知，见 Err
+)
+
+// Event 一条分发进度。单笔转账的 Recipient 不为空，批量转账的 Batch 从 1 开始，
+// 其他交易（部署合约、授权等）用 Label 说明用途
+type Event struct {
+	Kind      EventKind
+	Recipient *Recipient
+	Batch     int
+	Count     int // 批量交易包含的钱包数
+	Label     string
+	Amount    units.Amount // 单笔转账的代币数量（个）
+	Tx        common.Hash
+	Nonce     uint64
+	Block     uint64
+	GasUsed   uint64
+	Link      string // 区块浏览器中的交易链接
+	Reason    string
+	Err       error
+	Message   string
+}
+
+// subject 事件涉及的钱包或交易
+func (e Event) subject() string {
+	switch {
+	case e.Recipient != nil:
+		return e.Recipient.String()
+	case e.Batch > 0:
+		return fmt.Sprintf("第 %d 批(%d 个钱包)", e.Batch, e.Count)
+	}
+	return e.Label
+}
+
+// String 事件的日志文本
+func (e Event) String() string {
+	s, tx := e.subject(), e.Tx.Hex()
+	switch e.Kind {
+	case EventSkipped:
+		return fmt.Sprintf("%s，跳过：%s,Transaction-%s", e.Reason, s, tx)
+	case EventSubmitted:
+		if e.Recipient != nil {
+			return fmt.Sprintf("糖果分发已提交：%s,Transaction-%s,Nonce-%d,数量-%s", s, tx, e.Nonce, e.Amount)
+		}
+		return fmt.Sprintf("已广播：%s,Transaction-%s,Nonce-%d", s, tx, e.Nonce)
+	case EventFailed:
+		return fmt.Sprintf("糖果分发错误：%s,Error-%s", s, e.Err)
+	case EventMined:
+		msg := fmt.Sprintf("交易已打包：%s,Transaction-%s,区块-%d,Gas-%d", s, tx, e.Block, e.GasUsed)
+		if e.Link != "" {
+			msg += ",浏览器-" + e.Link
+		}
+		return msg
+	case EventReorged:
+		return fmt.Sprintf("区块重组，交易回到交易池：%s,Transaction-%s", s, tx)
+	case EventRebroadcast:
+		if e.Err != nil {
+			return fmt.Sprintf("重新广播失败：%s,Transaction-%s,Error-%s", s, tx, e.Err)
+		}
+		return fmt.Sprintf("交易不在交易池中，已重新广播：%s,Transaction-%s", s, tx)
+	case EventTrackError:
+		return fmt.Sprintf("查询交易状态失败：%s,Transaction-%s,Error-%s", s, tx, e.Err)
+	case EventConfirmed:
+		return fmt.Sprintf("糖果分发成功：%s,Transaction-%s,Gas-%d", s, tx, e.GasUsed)
+	case EventReverted:
+		return fmt.Sprintf("糖果分发交易执行失败：%s,Transaction-%s,Gas-%d,原因-%s", s, tx, e.GasUsed, e.Reason)
+	case EventDropped:
+		return fmt.Sprintf("糖果分发交易已被丢弃：%s,Transaction-%s", s, tx)
+	case EventTimeout:
+		return fmt.Sprintf("糖果分发交易等待确认超时：%s,Transaction-%s", s, tx)
+	case EventUnknown:
+		return fmt.Sprintf("跟踪糖果分发交易失败：%s,Transaction-%s,Error-%s", s, tx, e.Err)
+	}
+	return e.Message
+}
+
+// emit 输出一条事件，Close 之后跟踪器残留的回调直接丢弃
+func (ex *Executor) emit(e Event) {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	if ex.closed {
+		return
+	}
+	ex.events <- e
+}
+
+func (ex *Executor) logf(format string, args ...interface{}) {
+	ex.emit(Event{Kind: EventLog, Message: fmt.Sprintf(format, args...)})
+}
@@ -0,0 +1,423 @@
+package airdrop
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// journalStatus 分发日志中单个钱包的状态
+type journalStatus string
+
+const (
+	statusPending   journalStatus = "pending"   // 尚未签名发送
+	statusSent      journalStatus = "sent"      // 已签名广播，等待打包
+	statusConfirmed journalStatus = "confirmed" // 已打包且执行成功
+	statusFailed    journalStatus = "failed"    // 发送失败、执行失败或交易被丢弃，可以重发
+)
+
+type journalEntry struct {
+	Address common.Address `json:"address"`
+	Amount  units.Amount   `json:"amount"`
+	Status  journalStatus  `json:"status"`
+	TxHash  string         `json:"tx_hash,omitempty"`
+	Nonce   uint64         `json:"nonce"`
+	// RawTx 已签名交易的编码，只在 sent 状态保留，用于广播前崩溃后重新广播；
+	// 批量交易只记录在该批第一个钱包上
+	RawTx     string    `json:"raw_tx,omitempty"`
+	Block     uint64    `json:"block,omitempty"`
+	GasUsed   uint64    `json:"gas_used,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// journalHeader 分发日志的第一行，说明日志所属的代币与分发钱包
+type journalHeader struct {
+	Token  common.Address `json:"token"`
+	Sender common.Address `json:"sender"`
+	// Entries 只在旧格式（整个文件是一个 JSON 对象）中出现
+	Entries map[string]*journalEntry `json:"entries,omitempty"`
+}
+
+// journal 持久化的分发日志，每个目标钱包一条记录，用于中断后续传且不重复分发。
+// 文件为 JSON Lines：第一行是 journalHeader，之后每行是一条记录修改后的内容，同一钱包以最后一行为准。
+// 每次修改只追加一行；第一次写入与 close 时整体重写，去掉过期的行
+type journal struct {
+	path string
+	mu   sync.Mutex
+	file *os.File // 追加写入的文件，第一次写入时打开
+
+	Token   common.Address
+	Sender  common.Address
+	Entries map[string]*journalEntry
+}
+
+// JournalPath 钱包文件对应的分发日志文件
+func JournalPath(walletsFile string) string {
+	return walletsFile + ".journal"
+}
+
+// openJournal 打开分发日志，path 为空时只在内存中记录
+func openJournal(path string, token, sender common.Address) (*journal, error) {
+	j := &journal{
+		path:    path,
+		Token:   token,
+		Sender:  sender,
+		Entries: make(map[string]*journalEntry),
+	}
+	if path == "" {
+		return j, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取分发日志失败：%s", err)
+	}
+	if err := j.parse(data); err != nil {
+		return nil, fmt.Errorf("解析分发日志失败：%s,%s", path, err)
+	}
+	return j, nil
+}
+
+// parse 读取日志内容，崩溃时写了一半的最后一行直接忽略
+func (j *journal) parse(data []byte) error {
+	var header journalHeader
+	lines := bytes.Split(data, []byte("\n"))
+	if err := json.Unmarshal(lines[0], &header); err != nil {
+		// 旧格式：整个文件是一个 JSON 对象
+		if err := json.Unmarshal(data, &header); err != nil {
+			return err
+		}
+		lines = nil
+	} else {
+		lines = lines[1:]
+	}
+	if header.Token != j.Token || header.Sender != j.Sender {
+		return fmt.Errorf("日志属于代币 %s、钱包 %s，与本次分发不符", header.Token.Hex(), header.Sender.Hex())
+	}
+	for key, e := range header.Entries {
+		j.Entries[key] = e
+	}
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var e journalEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			if i == len(lines)-1 {
+				break
+			}
+			return fmt.Errorf("第 %d 行：%s", i+2, err)
+		}
+		j.Entries[journalKey(e.Address)] = &e
+	}
+	return nil
+}
+
+// resumeJournal 打开本次分发的日志并按链上状态更新，返回仍需分发的钱包；用完后调用 close
+func (ex *Executor) resumeJournal(ctx context.Context, plan *Plan) (*journal, []Recipient, error) {
+	jn, err := openJournal(plan.Journal, ex.token, ex.wallet)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := jn.reconcile(ctx, ex.client, ex.logf); err != nil {
+		jn.close()
+		return nil, nil, err
+	}
+	if err := jn.addPending(plan.Recipients, plan.Amount); err != nil {
+		jn.close()
+		return nil, nil, err
+	}
+	var todo []Recipient
+	for i := range plan.Recipients {
+		target := &plan.Recipients[i]
+		if e := jn.entry(target.Address); e != nil {
+			switch e.Status {
+			case statusConfirmed:
+				ex.emit(Event{Kind: EventSkipped, Recipient: target, Tx: common.HexToHash(e.TxHash), Reason: "已分发"})
+				continue
+			case statusSent:
+				ex.emit(Event{Kind: EventSkipped, Recipient: target, Tx: common.HexToHash(e.TxHash), Reason: "上次分发的交易尚未确认"})
+				continue
+			}
+		}
+		todo = append(todo, *target)
+	}
+	if skipped := len(plan.Recipients) - len(todo); skipped > 0 {
+		ex.logf("根据分发日志跳过 %d 个钱包", skipped)
+	}
+	return jn, todo, nil
+}
+
+func journalKey(addr common.Address) string {
+	return strings.ToLower(addr.Hex())
+}
+
+// entry 返回钱包对应的记录，不存在时返回 nil
+func (j *journal) entry(addr common.Address) *journalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if e, has := j.Entries[journalKey(addr)]; has {
+		cp := *e
+		return &cp
+	}
+	return nil
+}
+
+// addPending 为还没有记录的钱包建立 pending 记录
+func (j *journal) addPending(targets []Recipient, amount units.Amount) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var added []*journalEntry
+	for _, target := range targets {
+		if _, has := j.Entries[journalKey(target.Address)]; has {
+			continue
+		}
+		e := &journalEntry{
+			Address:   target.Address,
+			Amount:    target.AmountOr(amount),
+			Status:    statusPending,
+			UpdatedAt: time.Now(),
+		}
+		j.Entries[journalKey(target.Address)] = e
+		added = append(added, e)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	return j.append(added)
+}
+
+// update 修改钱包对应的记录并立即落盘
+func (j *journal) update(addr common.Address, fn func(e *journalEntry)) error {
+	return j.updateMany([]common.Address{addr}, fn)
+}
+
+// updateMany 批量修改多个钱包的记录，只落盘一次
+func (j *journal) updateMany(addrs []common.Address, fn func(e *journalEntry)) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	changed := make([]*journalEntry, 0, len(addrs))
+	for _, addr := range addrs {
+		e, has := j.Entries[journalKey(addr)]
+		if !has {
+			e = &journalEntry{Address: addr, Status: statusPending}
+			j.Entries[journalKey(addr)] = e
+		}
+		fn(e)
+		if e.Status != statusSent {
+			e.RawTx = ""
+		}
+		e.UpdatedAt = time.Now()
+		changed = append(changed, e)
+	}
+	return j.append(changed)
+}
+
+// append 把修改后的记录追加到日志末尾并同步到磁盘；第一次写入时先整体重写
+func (j *journal) append(entries []*journalEntry) error {
+	if j.path == "" {
+		return nil
+	}
+	if j.file == nil {
+		return j.compact()
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	if _, err := j.file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("写入分发日志失败：%s", err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return fmt.Errorf("写入分发日志失败：%s", err)
+	}
+	return nil
+}
+
+// compact 按当前记录重写日志，先写临时文件再替换，避免写到一半崩溃损坏日志；
+// 之后的修改追加到新文件
+func (j *journal) compact() error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	if err := enc.Encode(journalHeader{Token: j.Token, Sender: j.Sender}); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(j.Entries))
+	for key := range j.Entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := enc.Encode(j.Entries[key]); err != nil {
+			return err
+		}
+	}
+	if j.file != nil {
+		j.file.Close()
+		j.file = nil
+	}
+	tmp := j.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("写入分发日志失败：%s", err)
+	}
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return fmt.Errorf("写入分发日志失败：%s", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("写入分发日志失败：%s", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("写入分发日志失败：%s", err)
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		return fmt.Errorf("写入分发日志失败：%s", err)
+	}
+	j.file, err = os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("写入分发日志失败：%s", err)
+	}
+	return nil
+}
+
+// close 压缩日志并关闭文件，没有写入过时什么也不做
+func (j *journal) close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.compact()
+	if j.file != nil {
+		j.file.Close()
+		j.file = nil
+	}
+	return err
+}
+
+// reconcile 根据链上状态更新上次运行遗留的 sent 记录。交易不在链上也不在交易池中、Nonce 还没有被占用时，
+// 说明上次签名后没有广播成功（如广播前崩溃），按记录的已签名交易重新广播
+func (j *journal) reconcile(ctx context.Context, client ethutil.Backend, logf func(format string, args ...interface{})) error {
+	var sent []*journalEntry
+	raws := make(map[string]string) // 交易哈希到已签名交易，批量交易只记录在一个钱包上
+	j.mu.Lock()
+	for _, e := range j.Entries {
+		if e.Status == statusSent {
+			cp := *e
+			sent = append(sent, &cp)
+			if e.RawTx != "" {
+				raws[e.TxHash] = e.RawTx
+			}
+		}
+	}
+	j.mu.Unlock()
+	if len(sent) == 0 {
+		return nil
+	}
+	nonce, err := client.NonceAt(ctx, j.Sender, nil)
+	if err != nil {
+		return fmt.Errorf("获取钱包 Nonce 失败：%s", err)
+	}
+	// 同一笔批量交易只重新广播一次，之后的钱包沿用结果
+	rebroadcast := make(map[string]error)
+	for _, e := range sent {
+		hash := common.HexToHash(e.TxHash)
+		rp, err := client.TransactionReceipt(ctx, hash)
+		if err != nil && err != ethereum.NotFound {
+			return fmt.Errorf("查询交易 %s 失败：%s", e.TxHash, err)
+		}
+		if rp != nil {
+			status, errMsg := statusConfirmed, ""
+			if rp.Status != types.ReceiptStatusSuccessful {
+				status, errMsg = statusFailed, "交易执行失败"
+			}
+			if err := j.update(e.Address, func(e *journalEntry) {
+				e.Status = status
+				e.Error = errMsg
+				e.Block = rp.BlockNumber.Uint64()
+				e.GasUsed = rp.GasUsed
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		_, _, err = client.TransactionByHash(ctx, hash)
+		if err == nil {
+			// 交易仍在交易池中，保持 sent 状态
+			continue
+		}
+		if err != ethereum.NotFound {
+			return fmt.Errorf("查询交易 %s 失败：%s", e.TxHash, err)
+		}
+		// 交易池中找不到，且该 Nonce 已被其他交易占用，说明交易已被丢弃
+		if e.Nonce < nonce {
+			if err := j.update(e.Address, func(e *journalEntry) {
+				e.Status = statusFailed
+				e.Error = "交易已被丢弃"
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		sendErr, done := rebroadcast[e.TxHash]
+		if !done {
+			raw, has := raws[e.TxHash]
+			if !has {
+				// 没有记录已签名交易的旧日志，保持 sent 状态
+				continue
+			}
+			sendErr = sendRaw(ctx, client, raw)
+			rebroadcast[e.TxHash] = sendErr
+			if sendErr == nil {
+				logf("上次签名的交易没有广播，已重新广播：Transaction-%s", e.TxHash)
+			} else {
+				logf("重新广播上次签名的交易失败：Transaction-%s,Error-%s", e.TxHash, sendErr)
+			}
+		}
+		if ethutil.IsRejected(sendErr) {
+			// 交易被节点明确拒绝，没有发送，可以重发
+			if err := j.update(e.Address, func(e *journalEntry) {
+				e.Status = statusFailed
+				e.Error = sendErr.Error()
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sendRaw 广播已签名交易的编码
+func sendRaw(ctx context.Context, client ethutil.Backend, raw string) error {
+	data, err := hexutil.Decode(raw)
+	if err != nil {
+		return fmt.Errorf("解析已签名交易失败：%s", err)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("解析已签名交易失败：%s", err)
+	}
+	return client.SendTransaction(ctx, tx)
+}
@@ -0,0 +1,130 @@
+package airdrop
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	journalToken  = common.HexToAddress("0x000000000000000000000000000000000000700c")
+	journalSender = common.HexToAddress("0x0000000000000000000000000000000000005e4d")
+)
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.Count(data, []byte("\n"))
+}
+
+func TestJournalAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallets.txt.journal")
+	jn, err := openJournal(path, journalToken, journalSender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := recipients(0, 3)
+	// 第一次写入时整体写出：日志头与 3 条记录
+	if err := jn.addPending(targets, mustAmount("1")); err != nil {
+		t.Fatal(err)
+	}
+	if n := countLines(t, path); n != 4 {
+		t.Fatalf("日志 %d 行，预期 4 行", n)
+	}
+	// 之后每次修改只追加一行
+	for i, status := range []journalStatus{statusSent, statusConfirmed} {
+		if err := jn.update(targets[1].Address, func(e *journalEntry) {
+			e.Status, e.TxHash, e.RawTx = status, "0x01", "0xf8"
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if n := countLines(t, path); n != 5+i {
+			t.Fatalf("第 %d 次修改后日志 %d 行，预期 %d 行", i+1, n, 5+i)
+		}
+	}
+
+	// 重新打开时同一钱包以最后一行为准，离开 sent 状态后不再保留已签名交易
+	check := func(when string) {
+		t.Helper()
+		jn, err := openJournal(path, journalToken, journalSender)
+		if err != nil {
+			t.Fatalf("%s：%s", when, err)
+		}
+		if e := jn.entry(targets[1].Address); e == nil || e.Status != statusConfirmed || e.TxHash != "0x01" || e.RawTx != "" {
+			t.Errorf("%s：记录为 %+v", when, e)
+		}
+		if e := jn.entry(targets[0].Address); e == nil || e.Status != statusPending || e.Amount.String() != "2.5" {
+			t.Errorf("%s：记录为 %+v", when, e)
+		}
+	}
+	check("追加后")
+
+	// close 时去掉过期的行
+	if err := jn.close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := countLines(t, path); n != 4 {
+		t.Fatalf("压缩后日志 %d 行，预期 4 行", n)
+	}
+	check("压缩后")
+
+	// 崩溃时写了一半的最后一行直接忽略
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"address":"0x00000000000000000000000000000000000010`)
+	file.Close()
+	check("最后一行不完整")
+
+	if _, err := openJournal(path, journalToken, common.Address{}); err == nil {
+		t.Error("分发钱包不符时应当返回错误")
+	}
+}
+
+func TestJournalLegacyFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallets.txt.journal")
+	addr := common.HexToAddress("0x0000000000000000000000000000000000001000")
+	// 旧格式的日志整个文件是一个 JSON 对象
+	data, err := json.MarshalIndent(journalHeader{
+		Token:  journalToken,
+		Sender: journalSender,
+		Entries: map[string]*journalEntry{
+			journalKey(addr): {Address: addr, Amount: mustAmount("1"), Status: statusConfirmed, TxHash: "0x01"},
+		},
+	}, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	jn, err := openJournal(path, journalToken, journalSender)
+	if err != nil {
+		t.Fatalf("打开旧格式日志失败：%s", err)
+	}
+	if e := jn.entry(addr); e == nil || e.Status != statusConfirmed {
+		t.Fatalf("记录为 %+v，预期 confirmed", e)
+	}
+	// 第一次写入时改写为新格式
+	other := common.HexToAddress("0x0000000000000000000000000000000000001001")
+	if err := jn.update(other, func(e *journalEntry) { e.Status = statusFailed }); err != nil {
+		t.Fatal(err)
+	}
+	jn.close()
+	jn, err = openJournal(path, journalToken, journalSender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, b := jn.entry(addr), jn.entry(other); a == nil || a.Status != statusConfirmed || b == nil || b.Status != statusFailed {
+		t.Errorf("改写后的记录为 %+v 与 %+v", a, b)
+	}
+}
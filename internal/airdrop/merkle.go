@@ -0,0 +1,62 @@
+package airdrop
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/merkle"
+)
+
+// MerkleTree 按分发计划生成 Merkle 领取树，同一地址出现多次时数量累加
+func (ex *Executor) MerkleTree(plan *Plan) (*merkle.Tree, error) {
+	if err := ex.validate(plan); err != nil {
+		return nil, err
+	}
+	// 合约中每个地址只能领取一次
+	amounts := make(map[common.Address]*big.Int)
+	for _, target := range plan.Recipients {
+		num := ex.rawAmount(plan, target)
+		if prev, has := amounts[target.Address]; has {
+			num.Add(num, prev)
+		}
+		amounts[target.Address] = num
+	}
+	tree, err := merkle.NewTree(amounts)
+	if err != nil {
+		return nil, fmt.Errorf("生成 Merkle 树失败：%s", err)
+	}
+	return tree, nil
+}
+
+// DeployMerkle 部署领取合约并转入代币，返回合约地址
+func (ex *Executor) DeployMerkle(ctx context.Context, tree *merkle.Tree) (common.Address, error) {
+	ex.logf("正在部署领取合约")
+	opts, err := ex.transactOpts(ctx, ethutil.TxOptions{})
+	if err != nil {
+		return common.Address{}, fmt.Errorf("部署领取合约失败：%s", err)
+	}
+	addr, tx, _, err := merkle.Deploy(opts, ex.client, ex.token, tree)
+	if err != nil {
+		return addr, fmt.Errorf("部署领取合约失败：%s", err)
+	}
+	if err := ex.waitSuccess(ctx, tx, "部署领取合约"); err != nil {
+		return addr, fmt.Errorf("部署领取合约失败：%s", err)
+	}
+	ex.logf("领取合约已部署：%s，部署账户可以调用 sweep 收回无人领取的代币", addr.Hex())
+
+	if opts, err = ex.transactOpts(ctx, ethutil.TxOptions{}); err != nil {
+		return addr, fmt.Errorf("向领取合约转入代币失败：%s", err)
+	}
+	tx, err = ex.erc20.Transfer(opts, addr, tree.Total())
+	if err != nil {
+		return addr, fmt.Errorf("向领取合约转入代币失败：%s", err)
+	}
+	if err := ex.waitSuccess(ctx, tx, "向领取合约转入代币"); err != nil {
+		return addr, fmt.Errorf("向领取合约转入代币失败：%s", err)
+	}
+	ex.logf("已向领取合约转入 %s 个代币（最小单位）：Transaction-%s", tree.Total(), tx.Hash().String())
+	return addr, nil
+}
@@ -0,0 +1,249 @@
+package airdrop
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// pipeline 按本地分配的 Nonce 依次广播转账，同时最多有 Concurrency 笔交易等待打包
+type pipeline struct {
+	ex     *Executor
+	jn     *journal
+	nonces *ethutil.NonceManager
+	slots  chan struct{}
+	wg     sync.WaitGroup
+
+	mu  sync.Mutex
+	res *Result
+	// err 使本次分发停止的错误：交易被节点明确拒绝，或放弃跟踪的交易占用了之后交易的 Nonce
+	err    error
+	cancel context.CancelFunc // 停止跟踪已发送的交易
+}
+
+// pipelineDistribution 逐笔转账，结果计入 res。交易被节点明确拒绝时停止分配 Nonce，
+// 等待已发送的交易后返回节点的错误
+func (ex *Executor) pipelineDistribution(parent context.Context, plan *Plan, jn *journal, todo []Recipient, res *Result) error {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	nonces, err := ethutil.NewNonceManager(ctx, ex.client, ex.wallet)
+	if err != nil {
+		return fmt.Errorf("获取钱包 Nonce 失败：%s", err)
+	}
+	n := plan.Concurrency
+	if n < 1 {
+		n = 8
+	}
+	p := &pipeline{
+		ex:     ex,
+		jn:     jn,
+		nonces: nonces,
+		slots:  make(chan struct{}, n),
+		res:    res,
+		cancel: cancel,
+	}
+	for i := range todo {
+		if ctx.Err() != nil || p.failure() != nil {
+			break
+		}
+		p.send(ctx, &todo[i], todo[i].AmountOr(plan.Amount))
+	}
+	if !p.fillGaps(ctx) {
+		// 空缺之后的交易永远无法打包，停止跟踪，下次运行时按链上状态处理
+		cancel()
+	}
+	p.wg.Wait()
+	if err := p.failure(); err != nil {
+		return err
+	}
+	return parent.Err()
+}
+
+// fail 记录使分发停止的错误，之后不再发送新的转账；stop 为 true 时同时停止跟踪已发送的交易
+func (p *pipeline) fail(err error, stop bool) {
+	p.mu.Lock()
+	if p.err == nil {
+		p.err = err
+	}
+	p.mu.Unlock()
+	if stop {
+		p.cancel()
+	}
+}
+
+func (p *pipeline) failure() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+func (p *pipeline) count(n *int) {
+	p.mu.Lock()
+	*n++
+	p.mu.Unlock()
+}
+
+// send 广播一笔转账，成功后异步跟踪回执；交易池已满时阻塞
+func (p *pipeline) send(ctx context.Context, target *Recipient, num units.Amount) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return
+	}
+	if p.failure() != nil {
+		// 等待空位时其他交易已经失败
+		<-p.slots
+		return
+	}
+	raw, _ := p.ex.TokenAmount(num) // 计划已经通过 validate 检查
+	nonce := p.nonces.Next()
+	opts, err := p.ex.transactOpts(ctx, ethutil.TxOptions{Nonce: &nonce})
+	var signedTx *types.Transaction // 已签名的交易，签名后广播失败时交易仍可能已经发送
+	if err == nil {
+		signer := opts.Signer
+		opts.Signer = func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			signed, err := signer(addr, tx)
+			if err != nil {
+				return nil, err
+			}
+			raw, err := signed.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			// 广播前记录交易哈希与已签名交易，中断后据此判断是否已经发送，没有发送时重新广播
+			if err := p.jn.update(target.Address, func(e *journalEntry) {
+				e.Amount = num
+				e.Status = statusSent
+				e.TxHash = signed.Hash().Hex()
+				e.Nonce = signed.Nonce()
+				e.RawTx = hexutil.Encode(raw)
+				e.Error = ""
+			}); err != nil {
+				return nil, err
+			}
+			signedTx = signed
+			return signed, nil
+		}
+	}
+	var tx *types.Transaction
+	if err == nil {
+		tx, err = p.ex.erc20.Transfer(opts, target.Address, raw)
+	}
+	if err != nil && signedTx != nil {
+		if ethutil.IsRejected(err) {
+			// 节点明确拒绝了交易（余额不足、Gas 价格过低等），之后的交易也会被拒绝；
+			// 交易没有进入交易池，归还 Nonce 后停止分发
+			p.fail(fmt.Errorf("交易被节点拒绝，停止分发：钱包-%s,Transaction-%s,%s", target.Address.Hex(), signedTx.Hash().Hex(), err), false)
+		} else {
+			// Nonce 已经用于签名，广播失败（如网络错误）时交易仍可能进入了交易池，不能归还给其他交易，
+			// 按交易哈希跟踪，确认或丢弃后再记录结果
+			p.ex.logf("广播交易失败，交易可能已经发送，继续跟踪：钱包-%s,Transaction-%s,%s", target.Address.Hex(), signedTx.Hash().Hex(), err)
+			tx, err = signedTx, nil
+		}
+	}
+	if err != nil {
+		<-p.slots
+		p.nonces.Release(nonce)
+		p.count(&p.res.Rejected)
+		if reason := p.ex.tokenRevertReason(ctx, "transfer", target.Address, raw); reason != "" {
+			err = fmt.Errorf("%s，失败原因：%s", err, reason)
+		}
+		p.ex.emit(Event{Kind: EventFailed, Recipient: target, Amount: num, Err: err})
+		if err := p.jn.update(target.Address, func(e *journalEntry) {
+			e.Status = statusFailed
+			e.Error = err.Error()
+		}); err != nil {
+			p.ex.logf("%s", err)
+		}
+		return
+	}
+	p.ex.emit(Event{Kind: EventSubmitted, Recipient: target, Amount: num, Tx: tx.Hash(), Nonce: nonce})
+	p.wg.Add(1)
+	go p.track(ctx, target, num, tx)
+}
+
+// track 等待交易确认并记录结果
+func (p *pipeline) track(ctx context.Context, target *Recipient, num units.Amount, tx *types.Transaction) {
+	defer p.wg.Done()
+	defer func() { <-p.slots }()
+	subject := Event{Recipient: target, Amount: num, Tx: tx.Hash()}
+	res, err := p.ex.wait(ctx, tx, subject)
+	if err != nil {
+		p.count(&p.res.Unknown)
+		subject.Kind, subject.Err = EventUnknown, err
+		p.ex.emit(subject)
+		return
+	}
+	rp := res.Receipt
+	switch res.Status {
+	case ethutil.TxSuccess:
+		p.count(&p.res.Confirmed)
+		subject.Kind, subject.Block, subject.GasUsed = EventConfirmed, rp.BlockNumber.Uint64(), rp.GasUsed
+		p.ex.emit(subject)
+		p.setResult(target, statusConfirmed, "", rp)
+	case ethutil.TxReverted:
+		p.count(&p.res.Reverted)
+		reason := p.ex.revertReason(ctx, tx, rp)
+		subject.Kind, subject.Block, subject.GasUsed, subject.Reason = EventReverted, rp.BlockNumber.Uint64(), rp.GasUsed, reason
+		p.ex.emit(subject)
+		p.setResult(target, statusFailed, "交易执行失败："+reason, rp)
+	case ethutil.TxDropped:
+		p.count(&p.res.Dropped)
+		subject.Kind = EventDropped
+		p.ex.emit(subject)
+		p.setResult(target, statusFailed, "交易已被丢弃", nil)
+	case ethutil.TxTimeout:
+		// 保持 sent 状态，下次运行时按链上状态处理
+		p.count(&p.res.Unknown)
+		subject.Kind = EventTimeout
+		p.ex.emit(subject)
+	case ethutil.TxAbandoned:
+		// 保持 sent 状态；交易占用的 Nonce 没有上链，之后的交易都无法打包，停止分发
+		p.count(&p.res.Unknown)
+		subject.Kind, subject.Err = EventUnknown, res.Err
+		p.ex.emit(subject)
+		p.fail(fmt.Errorf("放弃跟踪交易，停止分发：钱包-%s,Transaction-%s,%s", target.Address.Hex(), tx.Hash().Hex(), res.Err), true)
+	}
+}
+
+func (p *pipeline) setResult(target *Recipient, status journalStatus, errMsg string, rp *types.Receipt) {
+	if err := p.jn.update(target.Address, func(e *journalEntry) {
+		e.Status = status
+		e.Error = errMsg
+		if rp != nil {
+			e.GasUsed = rp.GasUsed
+			e.Block = rp.BlockNumber.Uint64()
+		}
+	}); err != nil {
+		p.ex.logf("%s", err)
+	}
+}
+
+// fillGaps 用转给自己的 0 ETH 交易占用未复用的 Nonce，否则后面的交易永远无法打包；
+// 返回空缺是否都已补上
+func (p *pipeline) fillGaps(ctx context.Context) bool {
+	for p.nonces.Gaps() > 0 {
+		nonce := p.nonces.Next()
+		opts, err := p.ex.transactOpts(ctx, ethutil.TxOptions{Nonce: &nonce, GasLimit: 21000})
+		if err != nil {
+			p.ex.logf("填补 Nonce %d 失败：%s", nonce, err)
+			return false
+		}
+		signed, err := ethutil.SignTx(ctx, p.ex.client, opts, p.ex.chainID, p.ex.wallet, nil)
+		if err == nil {
+			err = p.ex.client.SendTransaction(ctx, signed)
+		}
+		if err != nil {
+			p.ex.logf("填补 Nonce %d 失败：%s", nonce, err)
+			return false
+		}
+		p.ex.logf("已填补 Nonce %d：Transaction-%s", nonce, signed.Hash().String())
+	}
+	return true
+}
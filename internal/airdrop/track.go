@@ -0,0 +1,92 @@
+package airdrop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/naiba/eth-tools/internal/disperse"
+	"github.com/naiba/eth-tools/internal/erc20"
+	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/merkle"
+)
+
+// wait 跟踪交易直到达到确认数，打包、区块重组与重新广播都作为事件输出，
+// subject 为事件中说明交易对象的部分（Recipient、Batch 或 Label）
+func (ex *Executor) wait(ctx context.Context, tx *types.Transaction, subject Event) (*ethutil.TxResult, error) {
+	subject.Tx = tx.Hash()
+	ch, err := ex.watcher.Watch(tx, func(ev ethutil.TrackEvent, rp *types.Receipt, err error) {
+		e := subject
+		e.Err = err
+		switch ev {
+		case ethutil.EventMined:
+			e.Kind = EventMined
+			e.Block, e.GasUsed = rp.BlockNumber.Uint64(), rp.GasUsed
+			e.Link = ex.network.TxURL(tx.Hash().Hex())
+		case ethutil.EventReorged:
+			e.Kind = EventReorged
+		case ethutil.EventRebroadcast:
+			e.Kind = EventRebroadcast
+		case ethutil.EventError:
+			e.Kind = EventTrackError
+		default:
+			return
+		}
+		ex.emit(e)
+	})
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case res, ok := <-ch:
+		if !ok {
+			return nil, errors.New("交易跟踪已停止")
+		}
+		return res, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// waitSuccess 等待交易成功，其他结果都作为错误返回
+func (ex *Executor) waitSuccess(ctx context.Context, tx *types.Transaction, label string) error {
+	res, err := ex.wait(ctx, tx, Event{Label: label})
+	if err != nil {
+		return err
+	}
+	if res.Status == ethutil.TxReverted {
+		return fmt.Errorf("交易执行失败：Transaction-%s,原因-%s", tx.Hash().String(), ex.revertReason(ctx, tx, res.Receipt))
+	}
+	if res.Status != ethutil.TxSuccess {
+		return fmt.Errorf("交易%s：Transaction-%s", res.Status, tx.Hash().String())
+	}
+	return nil
+}
+
+// revertABIs 解码自定义错误时使用的合约 ABI，第一个为代币合约
+var revertABIs = ethutil.MustParseABIs(erc20.Erc20ABI, disperse.DisperseABI, merkle.MerkleDistributorABI)
+
+// revertReason 重放执行失败的交易，返回失败原因
+func (ex *Executor) revertReason(ctx context.Context, tx *types.Transaction, rp *types.Receipt) string {
+	reason, err := ethutil.RevertReason(ctx, ex.client, tx, rp.BlockNumber, revertABIs...)
+	if err != nil {
+		return err.Error()
+	}
+	return reason
+}
+
+// tokenRevertReason 模拟调用代币合约，返回失败原因，调用能够成功时返回空字符串
+func (ex *Executor) tokenRevertReason(ctx context.Context, method string, args ...interface{}) string {
+	data, err := revertABIs[0].Pack(method, args...)
+	if err != nil {
+		return ""
+	}
+	msg := ethereum.CallMsg{From: ex.wallet, To: &ex.token, Data: data}
+	reason, err := ethutil.SimulateRevert(ctx, ex.client, msg, nil, revertABIs...)
+	if err != nil {
+		return ""
+	}
+	return reason
+}
@@ -0,0 +1,92 @@
+// Package candy 糖果分发器的界面与命令行共用的流程：导入钱包文件，连接节点后
+// 通过 airdrop 分发、预演或生成领取证明，进度逐条交给调用方输出；不依赖界面库
+package candy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/naiba/eth-tools/internal/airdrop"
+	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/keys"
+)
+
+// Logger 输出一条分发日志，界面写入日志框，命令行写到标准输出
+type Logger func(msg string)
+
+// dialAttempts 连接节点的最多尝试次数，节点一直不可用时放弃本次分发
+const dialAttempts = 10
+
+// execute 连接节点并创建分发引擎，进度写入日志；fn 返回后关闭引擎并等待日志输出完毕
+func execute(signer ethutil.Signer, o *Options, log Logger, fn func(ctx context.Context, ex *airdrop.Executor) error) error {
+	network := o.Network
+	fee, err := feeConfig(o)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	rc, url, err := network.DialRetry(ctx, dialAttempts, func(err error) {
+		log(fmt.Sprintf("网络错误，正在重连：%s", err))
+	})
+	if err != nil {
+		return err
+	}
+	log(fmt.Sprintf("已连接 %s：%s", network.Name, url))
+	ex, err := airdrop.NewExecutor(ctx, rc, airdrop.Config{
+		Network: network,
+		Signer:  signer,
+		Token:   common.HexToAddress(o.Token),
+		Fee:     fee,
+		Watch:   o.Watch,
+	})
+	if err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ev := range ex.Events() {
+			log(ev.String())
+		}
+	}()
+	err = fn(ctx, ex)
+	ex.Close()
+	<-done
+	return err
+}
+
+// OpenSigner 打开签名密钥，解密 keystore 需要几秒钟，界面应在后台调用
+func OpenSigner(spec keys.Spec, secret keys.Secret, log Logger) (ethutil.Signer, error) {
+	log("正在打开签名密钥：" + spec.String())
+	signer, err := keys.Open(spec, secret)
+	if err != nil {
+		return nil, err
+	}
+	log("签名账户：" + signer.Address().Hex())
+	return signer, nil
+}
+
+// Distribute 按设置分发糖果，部署了新的批量合约时把地址写回 o.Disperse
+func Distribute(signer ethutil.Signer, o *Options, log Logger) error {
+	plan := newPlan(o)
+	if err := plan.Validate(); err != nil {
+		return err
+	}
+	return execute(signer, o, log, func(ctx context.Context, ex *airdrop.Executor) error {
+		res, err := ex.Run(ctx, plan)
+		if res != nil && res.Disperse != (common.Address{}) {
+			// 界面把地址回填到输入框，之后的分发直接复用
+			o.Disperse = res.Disperse.Hex()
+		}
+		if err != nil {
+			return err
+		}
+		log(res.String())
+		if failed := res.Failed(); failed > 0 {
+			return fmt.Errorf("分发结束，%d/%d 个钱包分发失败", failed, res.Total)
+		}
+		log(fmt.Sprintf("分发结束，共分发 %d 个钱包", res.Total))
+		return nil
+	})
+}
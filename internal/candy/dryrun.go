@@ -0,0 +1,42 @@
+package candy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/naiba/eth-tools/internal/airdrop"
+	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// DryRun 预演一次分发：检查余额并估算 Gas，不广播任何交易
+func DryRun(signer ethutil.Signer, o *Options, log Logger) error {
+	plan := newPlan(o)
+	if err := plan.Validate(); err != nil {
+		return err
+	}
+	return execute(signer, o, log, func(ctx context.Context, ex *airdrop.Executor) error {
+		r, err := ex.DryRun(ctx, plan)
+		if err != nil {
+			return err
+		}
+		symbol := o.Network.Symbol
+		log(fmt.Sprintf("预演：共 %d 个钱包待分发，根据分发日志跳过 %d 个", r.Count, r.Skipped))
+		log(fmt.Sprintf("预演：代币总量 %s（最小单位 %s），钱包余额 %s", r.Tokens, r.TokensRaw, units.Format(r.TokenBalance, int(ex.Decimals()))))
+		log(fmt.Sprintf("预演：预计 %d 笔交易，Gas %s，手续费 %s，最多需要手续费 %s %s，钱包余额 %s %s", r.Txs, r.Gas, r.Fees, units.FormatEther(r.MaxFee), symbol, units.FormatEther(r.Balance), symbol))
+		if r.TokenShort() {
+			log("预演：代币余额不足")
+		}
+		if r.FeeShort() {
+			log(fmt.Sprintf("预演：%s 余额不足以支付手续费", symbol))
+		}
+		for _, f := range r.Failures {
+			log("预演：预计失败 " + f)
+		}
+		if err := r.Err(); err != nil {
+			return err
+		}
+		log("预演通过")
+		return nil
+	})
+}
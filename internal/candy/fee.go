@@ -0,0 +1,23 @@
+package candy
+
+import (
+	"github.com/naiba/eth-tools/internal/ethutil"
+)
+
+// feeConfig 根据分发设置生成手续费设置，没有指定交易类型时使用网络的默认值
+func feeConfig(o *Options) (ethutil.FeeConfig, error) {
+	cfg := ethutil.FeeConfig{Mode: o.Network.Fee}
+	var err error
+	if o.Fee != "" {
+		if cfg.Mode, err = ethutil.ParseFeeMode(o.Fee); err != nil {
+			return cfg, err
+		}
+	}
+	if cfg.MaxFeeCap, err = ethutil.ParseGwei(o.MaxFee); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxTipCap, err = ethutil.ParseGwei(o.MaxTip); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
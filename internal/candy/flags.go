@@ -0,0 +1,49 @@
+package candy
+
+import (
+	"flag"
+	"time"
+
+	"github.com/naiba/eth-tools/internal/ethutil"
+)
+
+// Flags 界面与命令行共用的参数
+type Flags struct {
+	Network       string
+	Networks      string
+	ChunkSize     int
+	Concurrency   int
+	Confirmations int
+	TxTimeout     time.Duration
+	Rebroadcasts  int
+}
+
+// RegisterFlags 在 fs 上注册共用的参数，需要在 Parse 之前调用
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{}
+	fs.StringVar(&f.Network, "network", "Mainnet", "使用网络配置中的哪个网络")
+	fs.StringVar(&f.Networks, "networks", "", "网络配置文件，默认为用户配置目录下的 eth-tools/networks.json")
+	fs.IntVar(&f.ChunkSize, "chunk", 100, "批量分发时每笔交易包含的钱包数")
+	fs.IntVar(&f.Concurrency, "concurrency", 8, "同时等待打包的交易数")
+	fs.IntVar(&f.Confirmations, "confirmations", 1, "交易打包后需要的确认块数，达到后才记为成功")
+	fs.DurationVar(&f.TxTimeout, "tx-timeout", 0, "每笔交易等待确认的最长时间，超时的交易保持已发送状态，下次运行时按链上状态处理；0 表示一直等待")
+	fs.IntVar(&f.Rebroadcasts, "max-rebroadcasts", 5, "交易不在交易池中时最多重新广播的次数，之后放弃跟踪并停止分发")
+	return f
+}
+
+// Options 按参数生成分发设置，其余字段由界面或命令行填写
+func (f *Flags) Options() *Options {
+	var depth uint64
+	if f.Confirmations > 0 {
+		depth = uint64(f.Confirmations)
+	}
+	return &Options{
+		ChunkSize:   f.ChunkSize,
+		Concurrency: f.Concurrency,
+		Watch: ethutil.WatcherOptions{
+			Confirmations:   depth,
+			Timeout:         f.TxTimeout,
+			MaxRebroadcasts: f.Rebroadcasts,
+		},
+	}
+}
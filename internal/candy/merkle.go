@@ -0,0 +1,43 @@
+package candy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/naiba/eth-tools/internal/airdrop"
+	"github.com/naiba/eth-tools/internal/ethutil"
+)
+
+// MerkleAirdrop 生成领取证明，按需部署领取合约并转入代币
+func MerkleAirdrop(signer ethutil.Signer, o *Options, log Logger) error {
+	plan := newPlan(o)
+	if err := plan.Validate(); err != nil {
+		return err
+	}
+	return execute(signer, o, log, func(ctx context.Context, ex *airdrop.Executor) error {
+		tree, err := ex.MerkleTree(plan)
+		if err != nil {
+			return err
+		}
+		out := o.MerkleOut
+		if out == "" {
+			out = o.WalletsFile + ".merkle.json"
+		}
+		dist := tree.Distribution()
+		data, err := json.MarshalIndent(dist, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(out, data, 0644); err != nil {
+			return fmt.Errorf("写入领取证明失败：%s", err)
+		}
+		log(fmt.Sprintf("已生成 %d 个地址的领取证明：%s,Merkle Root-%s,代币总量-%s", len(dist.Claims), out, tree.Root().Hex(), tree.Total()))
+		if !o.MerkleDeploy {
+			return nil
+		}
+		_, err = ex.DeployMerkle(ctx, tree)
+		return err
+	})
+}
@@ -0,0 +1,44 @@
+package candy
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/naiba/eth-tools/internal/airdrop"
+	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/netconf"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// Options 一次分发的设置，由界面或命令行在开始前生成，分发过程中不再读取界面与参数
+type Options struct {
+	Token        string
+	Network      netconf.Profile
+	Amount       units.Amount // 为 0 时以钱包文件中的数量为准
+	Wallets      []airdrop.Recipient
+	WalletsFile  string
+	Fee          string // 交易类型，留空使用网络的默认值
+	MaxFee       string // Gwei
+	MaxTip       string // Gwei
+	Batch        bool
+	Disperse     string // 分发时部署了新的批量合约会写回这里
+	ChunkSize    int
+	Concurrency  int
+	Watch        ethutil.WatcherOptions
+	MerkleOut    string // 领取证明文件，留空保存在钱包文件旁
+	MerkleDeploy bool
+}
+
+// newPlan 按导入的钱包与设置生成分发计划，分发日志保存在钱包文件旁
+func newPlan(o *Options) *airdrop.Plan {
+	plan := &airdrop.Plan{
+		Recipients:  o.Wallets,
+		Amount:      o.Amount,
+		Journal:     airdrop.JournalPath(o.WalletsFile),
+		Batch:       o.Batch,
+		ChunkSize:   o.ChunkSize,
+		Concurrency: o.Concurrency,
+	}
+	if o.Disperse != "" {
+		plan.Disperse = common.HexToAddress(o.Disperse)
+	}
+	return plan
+}
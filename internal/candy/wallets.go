@@ -0,0 +1,136 @@
+package candy
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/naiba/eth-tools/internal/airdrop"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// ParseWallets 导入目标钱包，每行格式为 地址[,数量[,备注]]，支持逗号或制表符分隔。
+// 所有问题会逐条输出，有错误时不导入任何钱包。
+func ParseWallets(path string, log Logger) ([]airdrop.Recipient, error) {
+	file, err := os.OpenFile(path, os.O_RDONLY, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("打开文件失败：%s,%s", path, err)
+	}
+	defer file.Close()
+	return readWallets(file, path, log)
+}
+
+// readWallets 从 r 中读取钱包，path 用于判断分隔符与错误信息
+func readWallets(r io.Reader, path string, log Logger) ([]airdrop.Recipient, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	if strings.EqualFold(filepath.Ext(path), ".tsv") {
+		reader.Comma = '\t'
+	} else {
+		// 分隔符为制表符时 TrimLeadingSpace 会吞掉空的数量列，字段在下面统一去掉空白
+		reader.TrimLeadingSpace = true
+	}
+
+	var errs, warns int
+	report := func(isErr bool, line int, format string, args ...interface{}) {
+		level := "警告"
+		if isErr {
+			errs++
+			level = "错误"
+		} else {
+			warns++
+		}
+		log(fmt.Sprintf("%s：第 %d 行，%s", level, line, fmt.Sprintf(format, args...)))
+	}
+	wallets := make([]airdrop.Recipient, 0)
+	seen := make(map[common.Address]int)
+	var rows int
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取文件失败：%s,%s", path, err)
+		}
+		line, _ := reader.FieldPos(0)
+		// 未指定 .tsv 扩展名的制表符文件
+		if len(record) == 1 && strings.Contains(record[0], "\t") {
+			record = strings.Split(record[0], "\t")
+		}
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+		// 跳过表头
+		if rows == 0 && strings.EqualFold(record[0], "address") {
+			continue
+		}
+		rows++
+		addr, err := parseAddress(record[0])
+		if err != nil {
+			report(true, line, "%s：%s", err, record[0])
+			continue
+		}
+		target := airdrop.Recipient{
+			Address: addr,
+			Line:    line,
+		}
+		if len(record) > 1 && record[1] != "" {
+			target.Amount, err = units.ParseAmount(record[1])
+			if err != nil || target.Amount.Sign() <= 0 {
+				report(true, line, "分发数量有误：%s", record[1])
+				continue
+			}
+		}
+		if len(record) > 2 {
+			target.Memo = record[2]
+		}
+		if addr == (common.Address{}) {
+			report(false, line, "零地址，已排除")
+			continue
+		}
+		// 重复地址合并到第一次出现的行：都写了数量时累加，都没写时只保留一个
+		if i, has := seen[addr]; has {
+			first := &wallets[i]
+			switch {
+			case first.Amount.Sign() > 0 && target.Amount.Sign() > 0:
+				first.Amount = first.Amount.Add(target.Amount)
+				report(false, line, "%s 与第 %d 行重复，数量已合并为 %s", addr.Hex(), first.Line, first.Amount)
+			case first.Amount.Sign() == 0 && target.Amount.Sign() == 0:
+				report(false, line, "%s 与第 %d 行重复，已排除", addr.Hex(), first.Line)
+			default:
+				report(true, line, "%s 与第 %d 行重复，且只有一行指定了数量", addr.Hex(), first.Line)
+			}
+			continue
+		}
+		seen[addr] = len(wallets)
+		wallets = append(wallets, target)
+	}
+	log(fmt.Sprintf("钱包文件校验完成：共 %d 行，有效钱包 %d 个，错误 %d 处，警告 %d 处", rows, len(wallets), errs, warns))
+	if errs > 0 {
+		return nil, fmt.Errorf("钱包文件有 %d 处错误，请修正后重新导入", errs)
+	}
+	if len(wallets) == 0 {
+		return nil, fmt.Errorf("钱包文件中没有可分发的钱包：%s", path)
+	}
+	return wallets, nil
+}
+
+// parseAddress 严格解析地址：必须是 40 位十六进制，大小写混合时按 EIP-55 校验
+func parseAddress(s string) (common.Address, error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, errors.New("地址格式错误")
+	}
+	addr := common.HexToAddress(s)
+	hex := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if hex != strings.ToLower(hex) && hex != strings.ToUpper(hex) && "0x"+hex != addr.Hex() {
+		return common.Address{}, errors.New("地址校验和（EIP-55）不匹配")
+	}
+	return addr, nil
+}
@@ -0,0 +1,146 @@
+package candy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadWallets(t *testing.T) {
+	const (
+		a = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+		b = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+	)
+	// wallet 预期导入的钱包：地址,数量,备注,行号
+	type wallet struct {
+		addr, amount, memo string
+		line               int
+	}
+	tests := []struct {
+		name    string
+		path    string
+		data    string
+		want    []wallet
+		err     string // 预期的错误，为空时预期导入成功
+		logs    []string
+		noticed int // 预期输出的警告与错误条数
+	}{
+		{
+			name: "地址与数量",
+			path: "w.csv",
+			data: a + ",1.5\n" + strings.ToLower(b) + "\n",
+			want: []wallet{{a, "1.5", "", 1}, {b, "0", "", 2}},
+		},
+		{
+			name: "跳过表头与注释",
+			path: "w.csv",
+			data: "address,amount,memo\n# 注释\n" + a + ",2,空投\n",
+			want: []wallet{{a, "2", "空投", 3}},
+		},
+		{
+			name: "tsv 文件带备注",
+			path: "w.TSV",
+			data: a + "\t1\t第一批, 社区\n" + b + "\t\t第二批\n",
+			want: []wallet{{a, "1", "第一批, 社区", 1}, {b, "0", "第二批", 2}},
+		},
+		{
+			name: "未指定扩展名的制表符文件",
+			path: "w.txt",
+			data: a + "\t3\t备注\n",
+			want: []wallet{{a, "3", "备注", 1}},
+		},
+		{
+			name:    "重复地址的数量合并",
+			path:    "w.csv",
+			data:    a + ",1\n" + b + ",1\n" + strings.ToLower(a) + ",0.5\n",
+			want:    []wallet{{a, "1.5", "", 1}, {b, "1", "", 2}},
+			logs:    []string{"警告：第 3 行", "数量已合并为 1.5"},
+			noticed: 1,
+		},
+		{
+			name:    "重复地址都没有数量时只保留一个",
+			path:    "w.csv",
+			data:    a + "\n" + a + "\n",
+			want:    []wallet{{a, "0", "", 1}},
+			logs:    []string{"与第 1 行重复，已排除"},
+			noticed: 1,
+		},
+		{
+			name:    "零地址被排除",
+			path:    "w.csv",
+			data:    "0x0000000000000000000000000000000000000000,1\n" + a + ",1\n",
+			want:    []wallet{{a, "1", "", 2}},
+			logs:    []string{"警告：第 1 行，零地址"},
+			noticed: 1,
+		},
+		{
+			name:    "十六进制有误",
+			path:    "w.csv",
+			data:    a + ",1\n0xf39Fd6e51aad88F6F4ce6aB8827279cffFb9226g,1\n0x1234,1\n",
+			err:     "2 处错误",
+			logs:    []string{"错误：第 2 行，地址格式错误", "错误：第 3 行，地址格式错误"},
+			noticed: 2,
+		},
+		{
+			name:    "EIP-55 校验和不匹配",
+			path:    "w.csv",
+			data:    "0xF39Fd6e51aad88F6F4ce6aB8827279cffFb92266,1\n",
+			err:     "1 处错误",
+			logs:    []string{"地址校验和（EIP-55）不匹配"},
+			noticed: 1,
+		},
+		{
+			name:    "数量有误",
+			path:    "w.csv",
+			data:    a + ",-1\n" + b + ",abc\n",
+			err:     "2 处错误",
+			logs:    []string{"分发数量有误：-1", "分发数量有误：abc"},
+			noticed: 2,
+		},
+		{
+			name:    "重复地址只有一行指定了数量",
+			path:    "w.csv",
+			data:    a + ",1\n" + a + "\n",
+			err:     "1 处错误",
+			logs:    []string{"只有一行指定了数量"},
+			noticed: 1,
+		},
+		{
+			name: "没有钱包",
+			path: "w.csv",
+			data: "address\n",
+			err:  "没有可分发的钱包",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs []string
+			wallets, err := readWallets(strings.NewReader(tt.data), tt.path, func(msg string) { logs = append(logs, msg) })
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("错误为 %v，预期包含 %q", err, tt.err)
+				}
+			} else if err != nil {
+				t.Fatalf("导入失败：%s", err)
+			}
+			if len(wallets) != len(tt.want) {
+				t.Fatalf("导入 %d 个钱包，预期 %d 个", len(wallets), len(tt.want))
+			}
+			for i, w := range tt.want {
+				got := wallets[i]
+				if got.Address.Hex() != w.addr || got.Amount.String() != w.amount || got.Memo != w.memo || got.Line != w.line {
+					t.Errorf("第 %d 个钱包为 %s,%s,%q,第 %d 行，预期 %s,%s,%q,第 %d 行", i, got.Address.Hex(), got.Amount, got.Memo, got.Line, w.addr, w.amount, w.memo, w.line)
+				}
+			}
+			all := strings.Join(logs, "\n")
+			for _, want := range tt.logs {
+				if !strings.Contains(all, want) {
+					t.Errorf("日志 %q 中没有 %q", all, want)
+				}
+			}
+			// 最后一条是校验汇总
+			if noticed := len(logs) - 1; noticed != tt.noticed {
+				t.Errorf("输出 %d 条问题，预期 %d 条：%q", noticed, tt.noticed, all)
+			}
+		})
+	}
+}
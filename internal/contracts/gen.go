@@ -0,0 +1,82 @@
+//go:build ignore
+
+// gen 把当前目录下的 *.easm 编译成带部署代码的 *.bin。
+// 构造函数参数按 ABI 顺序依次写入存储槽 0、1、2……，合约代码从对应的槽读取。
+package main
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/asm"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+func main() {
+	files, err := filepath.Glob("*.easm")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(file, ".easm")
+		source, err := ioutil.ReadFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		compiler := asm.NewCompiler(false)
+		compiler.Feed(asm.Lex(source, false))
+		code, errs := compiler.Compile()
+		if len(errs) > 0 {
+			log.Fatal(file, errs)
+		}
+		runtime, err := hex.DecodeString(code)
+		if err != nil {
+			log.Fatal(file, err)
+		}
+		abiFile, err := os.Open(name + ".abi")
+		if err != nil {
+			log.Fatal(err)
+		}
+		parsed, err := abi.JSON(abiFile)
+		abiFile.Close()
+		if err != nil {
+			log.Fatal(name+".abi", err)
+		}
+		bin := deployCode(runtime, len(parsed.Constructor.Inputs))
+		if err := ioutil.WriteFile(name+".bin", []byte(hex.EncodeToString(bin)+"\n"), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// deployCode 生成部署代码：把附在末尾的 args 个构造参数存入存储槽，然后返回 runtime
+func deployCode(runtime []byte, args int) []byte {
+	var init []byte
+	push2 := func(v int) {
+		init = append(init, byte(vm.PUSH2), byte(v>>8), byte(v))
+	}
+	if args > 0 {
+		// codecopy(0, codesize - 32*args, 32*args)
+		push2(32 * args)
+		init = append(init, byte(vm.DUP1), byte(vm.CODESIZE), byte(vm.SUB), byte(vm.PUSH1), 0, byte(vm.CODECOPY))
+		for i := 0; i < args; i++ {
+			// sstore(i, mload(32*i))
+			push2(32 * i)
+			init = append(init, byte(vm.MLOAD))
+			push2(i)
+			init = append(init, byte(vm.SSTORE))
+		}
+	}
+	// codecopy(0, len(init), len(runtime)); return(0, len(runtime))
+	push2(len(runtime))
+	init = append(init, byte(vm.DUP1))
+	// 之后还有 PUSH2、PUSH1 0、CODECOPY、PUSH1 0、RETURN 共 9 字节
+	push2(len(init) + 9)
+	init = append(init, byte(vm.PUSH1), 0, byte(vm.CODECOPY), byte(vm.PUSH1), 0, byte(vm.RETURN))
+	return append(init, runtime...)
+}
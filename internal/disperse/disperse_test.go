@@ -0,0 +1,149 @@
+package disperse
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/naiba/eth-tools/internal/testchain"
+)
+
+// disperseEnv 模拟链上的 Disperse 合约与测试代币，账户 0 持有代币并发起分发
+type disperseEnv struct {
+	chain     *testchain.Chain
+	contract  *Disperse
+	addr      common.Address
+	token     *testchain.TestToken
+	tokenAddr common.Address
+}
+
+func newDisperseEnv(t *testing.T, quiet bool) *disperseEnv {
+	t.Helper()
+	c := testchain.New(t, 1)
+	tokenAddr, token := c.DeployToken(t, 0, quiet)
+	c.Mint(t, token, c.Address(0), 1000)
+	addr, tx, contract, err := DeployDisperse(c.Transactor(t, 0), c)
+	if err != nil {
+		t.Fatalf("部署 Disperse 失败：%s", err)
+	}
+	if rp := c.Mine(t, tx); rp.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("部署 Disperse 失败")
+	}
+	return &disperseEnv{chain: c, contract: contract, addr: addr, token: token, tokenAddr: tokenAddr}
+}
+
+func (e *disperseEnv) approve(t *testing.T, value int64) {
+	t.Helper()
+	tx, err := e.token.Approve(e.chain.Transactor(t, 0), e.addr, big.NewInt(value))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.chain.Mine(t, tx)
+}
+
+// disperse 发送分发交易，返回回执状态与扣除固有成本后执行消耗的 Gas。
+// 指定 GasLimit 以便回滚的交易也能上链
+func (e *disperseEnv) disperse(t *testing.T, recipients []common.Address, values []*big.Int) (uint64, uint64) {
+	t.Helper()
+	opts := e.chain.Transactor(t, 0)
+	opts.GasLimit = 500000
+	tx, err := e.contract.DisperseToken(opts, e.tokenAddr, recipients, values)
+	if err != nil {
+		t.Fatalf("发送分发交易失败：%s", err)
+	}
+	rp := e.chain.Mine(t, tx)
+	intrinsic, err := core.IntrinsicGas(tx.Data(), nil, false, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rp.Status, rp.GasUsed - intrinsic
+}
+
+// preCallGas 在调用代币之前回滚时执行消耗的 Gas 上限，调用代币至少还要多花数千 Gas
+const preCallGas = 4000
+
+func (e *disperseEnv) balance(t *testing.T, addr common.Address) int64 {
+	t.Helper()
+	v, err := e.token.BalanceOf(&bind.CallOpts{}, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v.Int64()
+}
+
+var (
+	alice = common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	bob   = common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+)
+
+func values(v ...int64) []*big.Int {
+	out := make([]*big.Int, len(v))
+	for i, x := range v {
+		out[i] = big.NewInt(x)
+	}
+	return out
+}
+
+func TestDisperseToken(t *testing.T) {
+	for _, quiet := range []bool{false, true} {
+		e := newDisperseEnv(t, quiet)
+		e.approve(t, 300)
+		if s, _ := e.disperse(t, []common.Address{alice, bob, alice}, values(100, 150, 50)); s != types.ReceiptStatusSuccessful {
+			t.Fatalf("quiet=%v：分发交易执行失败", quiet)
+		}
+		for addr, want := range map[common.Address]int64{alice: 150, bob: 150, e.chain.Address(0): 700, e.addr: 0} {
+			if got := e.balance(t, addr); got != want {
+				t.Errorf("quiet=%v：%s 的余额 %d，预期 %d", quiet, addr.Hex(), got, want)
+			}
+		}
+		if v, _ := e.token.Allowance(&bind.CallOpts{}, e.chain.Address(0), e.addr); v.Sign() != 0 {
+			t.Errorf("quiet=%v：剩余授权 %s，预期 0", quiet, v)
+		}
+	}
+}
+
+func TestDisperseTokenRevert(t *testing.T) {
+	for _, c := range []struct {
+		name       string
+		allowance  int64
+		recipients []common.Address
+		values     []*big.Int
+		preCall    bool // 在调用代币之前回滚
+	}{
+		{"数组长度不一致", 300, []common.Address{alice, bob}, values(100), true},
+		{"授权不足", 249, []common.Address{alice, bob}, values(100, 150), false},
+		{"余额不足", 2000, []common.Address{alice, bob}, values(1000, 1), false},
+		// 不检查溢出时总量回绕成 1，授权 1 个代币就能通过 transferFrom
+		{"总量溢出", 1, []common.Address{alice, bob}, []*big.Int{math.MaxBig256, big.NewInt(2)}, true},
+		{"转给 0 地址", 300, []common.Address{alice, {}}, values(100, 150), false},
+	} {
+		e := newDisperseEnv(t, false)
+		e.approve(t, c.allowance)
+		s, gas := e.disperse(t, c.recipients, c.values)
+		if s != types.ReceiptStatusFailed {
+			t.Errorf("%s：分发交易应当回滚", c.name)
+		}
+		if (gas < preCallGas) != c.preCall {
+			t.Errorf("%s：执行消耗 %d Gas，是否在调用代币之前回滚应当为 %v", c.name, gas, c.preCall)
+		}
+		// 回滚后余额不变
+		if got := e.balance(t, e.chain.Address(0)); got != 1000 {
+			t.Errorf("%s：回滚后余额 %d，预期 1000", c.name, got)
+		}
+		if got := e.balance(t, alice); got != 0 {
+			t.Errorf("%s：回滚后 alice 的余额 %d，预期 0", c.name, got)
+		}
+	}
+}
+
+func TestDisperseTokenNotContract(t *testing.T) {
+	e := newDisperseEnv(t, false)
+	e.tokenAddr = alice
+	if s, _ := e.disperse(t, []common.Address{bob}, values(1)); s != types.ReceiptStatusFailed {
+		t.Error("代币地址上没有合约时应当回滚")
+	}
+}
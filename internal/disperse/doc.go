@@ -0,0 +1,5 @@
+// Package disperse 是批量转账合约 Disperse 的 Go 绑定，合约源码见 internal/contracts/Disperse.easm
+package disperse
+
+//go:generate sh -c "cd ../contracts && go run gen.go"
+//go:generate abigen --abi ../contracts/Disperse.abi --bin ../contracts/Disperse.bin --pkg disperse --type Disperse --out disperse.go
@@ -0,0 +1,5 @@
+// Package erc20 是 ERC-20 代币（含测试代币的 addToken、setToken）的 Go 绑定，ABI 见 Erc20.abi
+package erc20
+
+// 绑定由 go-ethereum v1.10.26 的 abigen 生成，升级 go-ethereum 后需要用同版本的 abigen 重新生成
+//go:generate abigen --abi Erc20.abi --pkg erc20 --type Erc20 --out erc20.go
@@ -0,0 +1,29 @@
+package ethutil
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Backend 签名、广播与跟踪交易用到的节点接口。*ethclient.Client 实现了它；
+// backends.SimulatedBackend 补上 ChainID 后也可以使用，便于在测试中模拟链。
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+
+	ChainID(ctx context.Context) (*big.Int, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// feeHistoryBackend 支持 eth_feeHistory 的节点，不支持时估算小费退回 eth_maxPriorityFeePerGas
+type feeHistoryBackend interface {
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+}
@@ -0,0 +1,34 @@
+package ethutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ChainMismatchError 节点的链 ID 与预期不符
+type ChainMismatchError struct {
+	Expected *big.Int
+	Actual   *big.Int
+}
+
+func (e *ChainMismatchError) Error() string {
+	return fmt.Sprintf("节点的链 ID 为 %s，与配置的链 ID %s 不符，可能连接到了错误的网络，已拒绝签名", e.Actual, e.Expected)
+}
+
+// VerifyChainID 用 eth_chainId 核对节点所在的链，不符时返回 *ChainMismatchError。
+// 不使用 NetworkID：不同的链可以有相同的网络 ID，签名只认链 ID。
+func VerifyChainID(ctx context.Context, client Backend, expected *big.Int) error {
+	if expected == nil || expected.Sign() <= 0 {
+		return errors.New("没有配置预期的链 ID，拒绝签名")
+	}
+	actual, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("获取链 ID 失败：%s", err)
+	}
+	if actual.Cmp(expected) != 0 {
+		return &ChainMismatchError{Expected: new(big.Int).Set(expected), Actual: actual}
+	}
+	return nil
+}
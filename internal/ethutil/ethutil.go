@@ -2,52 +2,87 @@ package ethutil
 
 import (
 	"context"
-	"crypto/ecdsa"
-	"log"
+	"fmt"
 	"math/big"
-	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-var lastNonce uint64
+// Signer 签名交易的账户，私钥可以不在本进程中，如远程签名服务
+type Signer interface {
+	Address() common.Address
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
 
-// TrackTxResult ...
-func TrackTxResult(clent *ethclient.Client, tx common.Hash) (bool, uint64, error) {
-	for {
-		rp, err := clent.TransactionReceipt(context.Background(), tx)
-		if err != nil && err != ethereum.NotFound {
-			return false, 0, err
-		}
-		if rp != nil {
-			return rp.Status == types.ReceiptStatusSuccessful, rp.GasUsed, err
-		}
-		time.Sleep(time.Second * 3)
-	}
+// TxOptions 生成交易参数的选项，零值表示使用默认行为
+type TxOptions struct {
+	GasLimit uint64    // 为 0 时发送前估算
+	Value    *big.Int  // 转账金额，单位 wei，为空时不转账
+	Fee      FeeConfig // 手续费设置
+	Nonce    *uint64   // 指定 Nonce，为空时使用节点的 pending Nonce
+	ChainID  *big.Int  // 预期的链 ID，必须指定，与节点不符时拒绝签名
 }
 
-// GenerateTransactOpts ...
-func GenerateTransactOpts(client *ethclient.Client, pk *ecdsa.PrivateKey, addr common.Address) *bind.TransactOpts {
-	nonce, err := client.PendingNonceAt(context.Background(), addr)
-	if err != nil {
-		log.Fatal("PendingNonceAt", err)
+// NewTransactOpts 生成签名交易所需的参数，手续费在此时估算。
+// 每次都会核对节点的链 ID，与 o.ChainID 不符时返回 *ChainMismatchError。
+func NewTransactOpts(ctx context.Context, client Backend, signer Signer, o TxOptions) (*bind.TransactOpts, error) {
+	if err := VerifyChainID(ctx, client, o.ChainID); err != nil {
+		return nil, err
+	}
+	from, chainID := signer.Address(), new(big.Int).Set(o.ChainID)
+	auth := &bind.TransactOpts{
+		From: from,
+		Signer: func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if addr != from {
+				return nil, bind.ErrNotAuthorized
+			}
+			return signer.SignTx(ctx, tx, chainID)
+		},
+		Context: ctx,
 	}
-	if nonce != 0 && nonce == lastNonce {
-		nonce++
+	auth.GasLimit = o.GasLimit
+	auth.Value = big.NewInt(0)
+	if o.Value != nil {
+		auth.Value = new(big.Int).Set(o.Value)
 	}
-	lastNonce = nonce
-	gasPrice, err := client.SuggestGasPrice(context.Background())
+	if o.Nonce != nil {
+		auth.Nonce = new(big.Int).SetUint64(*o.Nonce)
+	} else {
+		nonce, err := client.PendingNonceAt(ctx, auth.From)
+		if err != nil {
+			return nil, fmt.Errorf("获取钱包 Nonce 失败：%s", err)
+		}
+		auth.Nonce = new(big.Int).SetUint64(nonce)
+	}
+	fees, err := SuggestFees(ctx, client, o.Fee)
 	if err != nil {
-		log.Fatal("SuggestGasPrice", err)
+		return nil, err
+	}
+	fees.Apply(auth)
+	return auth, nil
+}
+
+// SignTx 按交易参数构造并签名一笔普通交易，没有指定 GasLimit 时先估算
+func SignTx(ctx context.Context, client Backend, opts *bind.TransactOpts, chainID *big.Int, to common.Address, data []byte) (*types.Transaction, error) {
+	gas := opts.GasLimit
+	if gas == 0 {
+		var err error
+		gas, err = client.EstimateGas(ctx, ethereum.CallMsg{
+			From:      opts.From,
+			To:        &to,
+			GasPrice:  opts.GasPrice,
+			GasFeeCap: opts.GasFeeCap,
+			GasTipCap: opts.GasTipCap,
+			Value:     opts.Value,
+			Data:      data,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("估算 Gas 失败：%s", err)
+		}
 	}
-	auth := bind.NewKeyedTransactor(pk)
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)      // in wei
-	auth.GasLimit = uint64(3000000) // in units
-	auth.GasPrice = gasPrice
-	return auth
+	tx := FeesOf(opts).Tx(chainID, opts.Nonce.Uint64(), to, opts.Value, gas, data)
+	return opts.Signer(opts.From, tx)
 }
@@ -0,0 +1,204 @@
+package ethutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// FeeMode 交易类型
+type FeeMode string
+
+const (
+	FeeAuto    FeeMode = "auto"   // 节点支持 EIP-1559 时使用动态费用，否则使用 Legacy
+	FeeLegacy  FeeMode = "legacy" // Legacy 交易，只有 GasPrice
+	FeeDynamic FeeMode = "1559"   // EIP-1559 交易，GasFeeCap + GasTipCap
+)
+
+// FeeModes 界面下拉框中的交易类型，顺序与 FeeModeNames 对应
+var FeeModes = []FeeMode{FeeAuto, FeeLegacy, FeeDynamic}
+
+// FeeModeNames 交易类型的显示名称
+var FeeModeNames = []string{"自动", "Legacy", "EIP-1559"}
+
+// ParseFeeMode 解析交易类型，空字符串视为 auto
+func ParseFeeMode(s string) (FeeMode, error) {
+	switch FeeMode(strings.ToLower(strings.TrimSpace(s))) {
+	case "", FeeAuto:
+		return FeeAuto, nil
+	case FeeLegacy:
+		return FeeLegacy, nil
+	case FeeDynamic, "eip1559", "eip-1559":
+		return FeeDynamic, nil
+	}
+	return "", fmt.Errorf("未知的交易类型：%s", s)
+}
+
+// FeeConfig 手续费设置，上限为 nil 时不限制
+type FeeConfig struct {
+	Mode      FeeMode
+	MaxFeeCap *big.Int // Legacy 交易的最高 GasPrice，或 EIP-1559 交易的最高 GasFeeCap，单位 wei
+	MaxTipCap *big.Int // EIP-1559 交易的最高小费，单位 wei
+}
+
+// Fees 一笔交易实际使用的手续费，GasFeeCap 不为空时为 EIP-1559 交易
+type Fees struct {
+	GasPrice  *big.Int
+	GasFeeCap *big.Int
+	GasTipCap *big.Int
+}
+
+// feeHistoryBlocks 估算小费时参考的最近区块数
+const feeHistoryBlocks = 10
+
+// SuggestFees 按设置估算手续费：EIP-1559 交易的小费取最近区块的小费中位数，
+// GasFeeCap 为两倍基础费用加小费，两者都不超过用户设置的上限
+func SuggestFees(ctx context.Context, client Backend, cfg FeeConfig) (*Fees, error) {
+	mode := cfg.Mode
+	var baseFee *big.Int
+	if mode != FeeLegacy {
+		head, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("获取最新区块失败：%s", err)
+		}
+		baseFee = head.BaseFee
+		if baseFee == nil {
+			if mode == FeeDynamic {
+				return nil, errors.New("节点不支持 EIP-1559 交易")
+			}
+			mode = FeeLegacy
+		}
+	}
+
+	if mode == FeeLegacy {
+		gasPrice, err := client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("获取 Gas 价格失败：%s", err)
+		}
+		if cfg.MaxFeeCap != nil && gasPrice.Cmp(cfg.MaxFeeCap) > 0 {
+			gasPrice = new(big.Int).Set(cfg.MaxFeeCap)
+		}
+		return &Fees{GasPrice: gasPrice}, nil
+	}
+
+	tip, err := suggestTip(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MaxTipCap != nil && tip.Cmp(cfg.MaxTipCap) > 0 {
+		tip = new(big.Int).Set(cfg.MaxTipCap)
+	}
+	feeCap := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tip)
+	if cfg.MaxFeeCap != nil && feeCap.Cmp(cfg.MaxFeeCap) > 0 {
+		feeCap = new(big.Int).Set(cfg.MaxFeeCap)
+	}
+	if feeCap.Cmp(baseFee) < 0 {
+		return nil, fmt.Errorf("最高费用 %s 低于当前基础费用 %s，交易无法打包", FormatGwei(feeCap), FormatGwei(baseFee))
+	}
+	if tip.Cmp(feeCap) > 0 {
+		tip = new(big.Int).Set(feeCap)
+	}
+	return &Fees{GasFeeCap: feeCap, GasTipCap: tip}, nil
+}
+
+// suggestTip 取最近区块小费中位数的平均值，节点不支持 eth_feeHistory 时退回 eth_maxPriorityFeePerGas
+func suggestTip(ctx context.Context, client Backend) (*big.Int, error) {
+	if fh, ok := client.(feeHistoryBackend); ok {
+		if history, err := fh.FeeHistory(ctx, feeHistoryBlocks, nil, []float64{50}); err == nil {
+			sum, n := new(big.Int), 0
+			for _, reward := range history.Reward {
+				// 空块的小费为 0，不参与平均
+				if len(reward) == 0 || reward[0].Sign() == 0 {
+					continue
+				}
+				sum.Add(sum, reward[0])
+				n++
+			}
+			if n > 0 {
+				return sum.Div(sum, big.NewInt(int64(n))), nil
+			}
+		}
+	}
+	tip, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取小费失败：%s", err)
+	}
+	return tip, nil
+}
+
+// Dynamic 是否为 EIP-1559 交易
+func (f *Fees) Dynamic() bool {
+	return f.GasFeeCap != nil
+}
+
+// MaxPrice 每单位 Gas 最多支付的费用，用于估算手续费
+func (f *Fees) MaxPrice() *big.Int {
+	if f.Dynamic() {
+		return f.GasFeeCap
+	}
+	return f.GasPrice
+}
+
+// Apply 把手续费写入交易参数
+func (f *Fees) Apply(opts *bind.TransactOpts) {
+	opts.GasPrice = f.GasPrice
+	opts.GasFeeCap = f.GasFeeCap
+	opts.GasTipCap = f.GasTipCap
+}
+
+// FeesOf 取出交易参数中的手续费
+func FeesOf(opts *bind.TransactOpts) *Fees {
+	return &Fees{GasPrice: opts.GasPrice, GasFeeCap: opts.GasFeeCap, GasTipCap: opts.GasTipCap}
+}
+
+// Tx 按手续费类型构造一笔交易
+func (f *Fees) Tx(chainID *big.Int, nonce uint64, to common.Address, value *big.Int, gas uint64, data []byte) *types.Transaction {
+	if f.Dynamic() {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: f.GasTipCap,
+			GasFeeCap: f.GasFeeCap,
+			Gas:       gas,
+			To:        &to,
+			Value:     value,
+			Data:      data,
+		})
+	}
+	return types.NewTransaction(nonce, to, value, gas, f.GasPrice, data)
+}
+
+func (f *Fees) String() string {
+	if f.Dynamic() {
+		return fmt.Sprintf("EIP-1559,最高费用-%s,小费-%s", FormatGwei(f.GasFeeCap), FormatGwei(f.GasTipCap))
+	}
+	return fmt.Sprintf("Legacy,GasPrice-%s", FormatGwei(f.GasPrice))
+}
+
+// ParseGwei 把 Gwei 数值解析为 wei，也可以带单位，如 "1.5 gwei"，空字符串返回 nil
+func ParseGwei(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	v, err := units.ParseIn(s, "gwei")
+	if err != nil {
+		return nil, fmt.Errorf("费用格式错误：%s", err)
+	}
+	return v, nil
+}
+
+// FormatGwei 把 wei 格式化为 Gwei
+func FormatGwei(wei *big.Int) string {
+	if wei == nil {
+		return "-"
+	}
+	return units.Format(wei, 9) + " Gwei"
+}
@@ -0,0 +1,111 @@
+package ethutil
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NonceManager 在本地为同一个钱包分配 Nonce，只在创建时查询一次 PendingNonceAt
+type NonceManager struct {
+	mu       sync.Mutex
+	next     uint64
+	released []uint64
+}
+
+// NewNonceManager 从节点的 pending Nonce 开始分配
+func NewNonceManager(ctx context.Context, client Backend, addr common.Address) (*NonceManager, error) {
+	nonce, err := client.PendingNonceAt(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	return &NonceManager{next: nonce}, nil
+}
+
+// Next 分配一个 Nonce，优先复用归还的 Nonce 以填补空缺
+func (m *NonceManager) Next() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.released) > 0 {
+		nonce := m.released[0]
+		m.released = m.released[1:]
+		return nonce
+	}
+	nonce := m.next
+	m.next++
+	return nonce
+}
+
+// Release 归还没有进入交易池的 Nonce。归还的 Nonce 连到末尾时直接收回，
+// 不再作为空缺，以免为它们发送多余的填补交易。
+func (m *NonceManager) Release(nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.released = append(m.released, nonce)
+	sort.Slice(m.released, func(i, j int) bool { return m.released[i] < m.released[j] })
+	for n := len(m.released); n > 0 && m.released[n-1]+1 == m.next; n-- {
+		m.next--
+		m.released = m.released[:n-1]
+	}
+}
+
+// Gaps 返回归还后尚未复用的 Nonce 个数，这些 Nonce 之后的交易在空缺补上前无法打包
+func (m *NonceManager) Gaps() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.released)
+}
+
+// nonceErrors 节点因 Nonce 拒绝交易时的错误信息，远程节点只返回文字，只能按内容判断
+var nonceErrors = []string{
+	"nonce too low",
+	"nonce too high",
+	"already known",
+	"known transaction",
+	"replacement transaction underpriced",
+}
+
+// IsNonceError 交易是否因 Nonce 与节点不一致而被拒绝，此时本地分配的 Nonce 需要重新同步
+func IsNonceError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range nonceErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// rejectErrors 节点明确拒绝交易时的错误信息，重新广播同一笔交易也不会被接受
+var rejectErrors = []string{
+	"insufficient funds",
+	"underpriced",
+	"intrinsic gas too low",
+	"exceeds block gas limit",
+	"max fee per gas less than",
+	"max priority fee per gas higher than",
+	"exceeds the configured cap",
+	"oversized data",
+	"invalid sender",
+}
+
+// IsRejected 交易是否被节点明确拒绝（余额不足、Gas 价格过低、Gas 上限过低等），
+// 这时交易不在交易池中，Nonce 也没有被占用；Nonce 错误与网络错误不算，交易可能已经发送
+func IsRejected(err error) bool {
+	if err == nil || IsNonceError(err) {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range rejectErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
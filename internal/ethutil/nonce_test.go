@@ -0,0 +1,85 @@
+package ethutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNonceManagerRelease(t *testing.T) {
+	m := &NonceManager{next: 10}
+	for want := uint64(10); want < 15; want++ {
+		if got := m.Next(); got != want {
+			t.Fatalf("Next() = %d，预期 %d", got, want)
+		}
+	}
+	// 中间归还的 Nonce 是空缺，下次优先复用
+	m.Release(11)
+	m.Release(12)
+	if m.Gaps() != 2 {
+		t.Fatalf("Gaps() = %d，预期 2", m.Gaps())
+	}
+	// 归还末尾的 Nonce 后，之前归还的 11、12 也连到了末尾，一起收回
+	m.Release(13)
+	m.Release(14)
+	if m.Gaps() != 0 || m.next != 11 {
+		t.Fatalf("Gaps() = %d，next = %d，预期 0 与 11", m.Gaps(), m.next)
+	}
+	if got := m.Next(); got != 11 {
+		t.Fatalf("Next() = %d，预期 11", got)
+	}
+
+	// 乱序归还
+	m = &NonceManager{next: 0}
+	for i := 0; i < 5; i++ {
+		m.Next()
+	}
+	m.Release(1)
+	m.Release(4)
+	m.Release(3)
+	if m.Gaps() != 1 || m.next != 3 {
+		t.Fatalf("Gaps() = %d，next = %d，预期 1 与 3", m.Gaps(), m.next)
+	}
+	for _, want := range []uint64{1, 3, 4} {
+		if got := m.Next(); got != want {
+			t.Fatalf("Next() = %d，预期 %d", got, want)
+		}
+	}
+}
+
+func TestIsNonceError(t *testing.T) {
+	for _, msg := range []string{"nonce too low", "Nonce too high", "already known", "replacement transaction underpriced"} {
+		if !IsNonceError(errors.New(msg)) {
+			t.Errorf("%q 应当是 Nonce 错误", msg)
+		}
+	}
+	for _, err := range []error{nil, errors.New("insufficient funds for gas * price + value")} {
+		if IsNonceError(err) {
+			t.Errorf("%v 不应当是 Nonce 错误", err)
+		}
+	}
+}
+
+func TestIsRejected(t *testing.T) {
+	for _, msg := range []string{
+		"insufficient funds for gas * price + value",
+		"transaction underpriced",
+		"intrinsic gas too low",
+		"exceeds block gas limit",
+		"max fee per gas less than block base fee",
+	} {
+		if !IsRejected(errors.New(msg)) {
+			t.Errorf("%q 应当是节点拒绝交易", msg)
+		}
+	}
+	for _, err := range []error{
+		nil,
+		errors.New("already known"),
+		errors.New("nonce too low"),
+		errors.New("replacement transaction underpriced"),
+		errors.New("Post \"http://127.0.0.1:8545\": dial tcp 127.0.0.1:8545: connect: connection refused"),
+	} {
+		if IsRejected(err) {
+			t.Errorf("%v 不应当是节点拒绝交易", err)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package ethutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	errorSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+)
+
+// panicReasons Solidity 内置 Panic(uint256) 错误码的含义
+var panicReasons = map[uint64]string{
+	0x00: "编译器内置的通用错误",
+	0x01: "assert 失败",
+	0x11: "算术运算溢出",
+	0x12: "除以零或对零取模",
+	0x21: "转换为枚举时越界",
+	0x22: "存储中的字节数组编码错误",
+	0x31: "对空数组调用 pop",
+	0x32: "数组下标越界",
+	0x41: "内存分配过多",
+	0x51: "调用了未初始化的函数变量",
+}
+
+// ParseABIs 解析多个合约 ABI，用于解码自定义错误
+func ParseABIs(jsons ...string) ([]*abi.ABI, error) {
+	abis := make([]*abi.ABI, 0, len(jsons))
+	for _, j := range jsons {
+		parsed, err := abi.JSON(strings.NewReader(j))
+		if err != nil {
+			return nil, fmt.Errorf("解析 ABI 失败：%s", err)
+		}
+		abis = append(abis, &parsed)
+	}
+	return abis, nil
+}
+
+// MustParseABIs 与 ParseABIs 相同，出错时 panic，只用于包级变量中生成的绑定 ABI
+func MustParseABIs(jsons ...string) []*abi.ABI {
+	abis, err := ParseABIs(jsons...)
+	if err != nil {
+		panic(err)
+	}
+	return abis
+}
+
+// DecodeRevert 解码合约返回的失败数据：Error(string)、Panic(uint256)，
+// 以及在 abis 中定义的自定义错误，无法识别时返回十六进制原文
+func DecodeRevert(data []byte, abis ...*abi.ABI) string {
+	if len(data) == 0 {
+		return "没有返回失败原因"
+	}
+	if len(data) >= 4 {
+		switch {
+		case bytes.Equal(data[:4], errorSelector):
+			if reason, err := abi.UnpackRevert(data); err == nil {
+				return reason
+			}
+		case bytes.Equal(data[:4], panicSelector) && len(data) == 36:
+			code := new(big.Int).SetBytes(data[4:])
+			if reason, has := panicReasons[code.Uint64()]; has && code.IsUint64() {
+				return fmt.Sprintf("Panic(0x%x)：%s", code, reason)
+			}
+			return fmt.Sprintf("Panic(0x%x)", code)
+		}
+		for _, parsed := range abis {
+			for _, e := range parsed.Errors {
+				if !bytes.Equal(data[:4], e.ID[:4]) {
+					continue
+				}
+				values, err := e.Unpack(data)
+				if err != nil {
+					continue
+				}
+				return formatCustomError(e, values)
+			}
+		}
+	}
+	return "无法识别的失败数据：" + hexutil.Encode(data)
+}
+
+func formatCustomError(e abi.Error, values interface{}) string {
+	list, ok := values.([]interface{})
+	if !ok {
+		return fmt.Sprintf("%s(%v)", e.Name, values)
+	}
+	args := make([]string, len(list))
+	for i, v := range list {
+		name := ""
+		if i < len(e.Inputs) && e.Inputs[i].Name != "" {
+			name = e.Inputs[i].Name + "="
+		}
+		switch v := v.(type) {
+		case common.Address:
+			args[i] = name + v.Hex()
+		case [32]byte:
+			args[i] = name + hexutil.Encode(v[:])
+		case []byte:
+			args[i] = name + hexutil.Encode(v)
+		default:
+			args[i] = fmt.Sprintf("%s%v", name, v)
+		}
+	}
+	return e.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// RevertData 取出节点返回的 revert 数据，错误中没有附带数据时返回 false
+func RevertData(err error) ([]byte, bool) {
+	var de rpc.DataError
+	if !errors.As(err, &de) {
+		return nil, false
+	}
+	s, ok := de.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+	data, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// SimulateRevert 用 eth_call 模拟调用并解码失败原因，调用成功时返回空字符串
+func SimulateRevert(ctx context.Context, client Backend, msg ethereum.CallMsg, block *big.Int, abis ...*abi.ABI) (string, error) {
+	_, err := client.CallContract(ctx, msg, block)
+	if err == nil {
+		return "", nil
+	}
+	if data, ok := RevertData(err); ok {
+		return DecodeRevert(data, abis...), nil
+	}
+	// 节点没有返回 revert 数据，只能使用错误信息
+	if err.Error() == "execution reverted" {
+		return DecodeRevert(nil), nil
+	}
+	if strings.HasPrefix(err.Error(), "execution reverted") {
+		return err.Error(), nil
+	}
+	return "", fmt.Errorf("模拟调用失败：%s", err)
+}
+
+// RevertReason 在交易所在区块之前的状态上用 eth_call 重放一笔失败的交易并解码失败原因
+func RevertReason(ctx context.Context, client Backend, tx *types.Transaction, block *big.Int, abis ...*abi.ABI) (string, error) {
+	from, err := txSender(tx)
+	if err != nil {
+		return "", fmt.Errorf("解析交易发送方失败：%s", err)
+	}
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	var parent *big.Int
+	if block != nil && block.Sign() > 0 {
+		parent = new(big.Int).Sub(block, big.NewInt(1))
+	}
+	reason, err := SimulateRevert(ctx, client, msg, parent, abis...)
+	if err != nil {
+		return "", err
+	}
+	if reason == "" {
+		// 单独重放成功，失败与同一区块中排在前面的交易有关
+		return "重放时执行成功，失败可能与同一区块中的其他交易有关", nil
+	}
+	return reason, nil
+}
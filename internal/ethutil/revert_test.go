@@ -0,0 +1,97 @@
+package ethutil
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/naiba/eth-tools/internal/erc20"
+)
+
+// customErrorABI OpenZeppelin 5 的 ERC-20 自定义错误，生成的 erc20 绑定中没有定义错误
+const customErrorABI = `[{"type":"error","name":"ERC20InsufficientBalance","inputs":[{"name":"sender","type":"address"},{"name":"balance","type":"uint256"},{"name":"needed","type":"uint256"}]}]`
+
+// packRevert 按 ABI 编码失败数据：4 字节选择器加参数
+func packRevert(t *testing.T, selector []byte, types []string, values ...interface{}) []byte {
+	t.Helper()
+	var args abi.Arguments
+	for _, name := range types {
+		typ, err := abi.NewType(name, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		args = append(args, abi.Argument{Type: typ})
+	}
+	data, err := args.Pack(values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(append([]byte{}, selector...), data...)
+}
+
+func TestDecodeRevert(t *testing.T) {
+	abis := MustParseABIs(erc20.Erc20ABI, customErrorABI)
+	sender := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	id := abis[1].Errors["ERC20InsufficientBalance"].ID
+	custom := packRevert(t, id[:4], []string{"address", "uint256", "uint256"}, sender, big.NewInt(1), big.NewInt(2))
+	tests := []struct {
+		name string
+		data []byte
+		abis []*abi.ABI
+		want string
+	}{
+		{"Error(string)", packRevert(t, errorSelector, []string{"string"}, "余额不足"), nil, "余额不足"},
+		{"Panic(0x11)", packRevert(t, panicSelector, []string{"uint256"}, big.NewInt(0x11)), nil, "Panic(0x11)：算术运算溢出"},
+		{"未知的 Panic 错误码", packRevert(t, panicSelector, []string{"uint256"}, big.NewInt(0x99)), nil, "Panic(0x99)"},
+		{"自定义错误", custom, abis, "ERC20InsufficientBalance(sender=0x70997970C51812dc3A010C7d01b50e0d17dc79C8, balance=1, needed=2)"},
+		{"没有传入定义自定义错误的 ABI", custom, abis[:1], "无法识别的失败数据：" + hexutil.Encode(custom)},
+		{"自定义错误的参数被截断", custom[:40], abis, "无法识别的失败数据：" + hexutil.Encode(custom[:40])},
+		{"未知的选择器", []byte{0xde, 0xad, 0xbe, 0xef}, abis, "无法识别的失败数据：0xdeadbeef"},
+		{"没有数据", nil, abis, "没有返回失败原因"},
+		{"不足 4 字节", []byte{0x08, 0xc3}, abis, "无法识别的失败数据：0x08c3"},
+		{"Error(string) 数据被截断", errorSelector, nil, "无法识别的失败数据：0x08c379a0"},
+		{"Panic 数据长度不对", append(append([]byte{}, panicSelector...), 0x11), nil, "无法识别的失败数据：0x4e487b7111"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecodeRevert(tt.data, tt.abis...); got != tt.want {
+				t.Errorf("DecodeRevert(%x) = %q，预期 %q", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+// dataError 附带 revert 数据的节点错误，与 rpc 客户端返回的错误相同
+type dataError struct {
+	data interface{}
+}
+
+func (e *dataError) Error() string          { return "execution reverted" }
+func (e *dataError) ErrorData() interface{} { return e.data }
+
+func TestRevertData(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string // 为空时预期没有数据
+	}{
+		{"十六进制数据", &dataError{"0xdeadbeef"}, "0xdeadbeef"},
+		{"包装过的错误", fmt.Errorf("估算 Gas 失败：%w", &dataError{"0x4e487b71"}), "0x4e487b71"},
+		{"数据不是字符串", &dataError{map[string]interface{}{"message": "x"}}, ""},
+		{"数据不是十六进制", &dataError{"reverted"}, ""},
+		{"没有数据", errors.New("execution reverted"), ""},
+		{"nil", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, ok := RevertData(tt.err)
+			if ok != (tt.want != "") || (ok && hexutil.Encode(data) != tt.want) {
+				t.Errorf("RevertData(%v) = %x, %v，预期 %q", tt.err, data, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxStatus 交易的最终结果
+type TxStatus int
+
+const (
+	TxSuccess   TxStatus = iota // 打包且执行成功，已达到确认数
+	TxReverted                  // 打包但执行失败，已达到确认数
+	TxDropped                   // 交易被丢弃，Nonce 已被其他交易占用
+	TxTimeout                   // 超时仍未达到确认数
+	TxAbandoned                 // 交易不在交易池中，重新广播多次或被节点拒绝后放弃，交易仍可能被打包
+)
+
+func (s TxStatus) String() string {
+	switch s {
+	case TxSuccess:
+		return "成功"
+	case TxReverted:
+		return "执行失败"
+	case TxDropped:
+		return "已丢弃"
+	case TxTimeout:
+		return "超时"
+	case TxAbandoned:
+		return "已放弃"
+	}
+	return fmt.Sprintf("TxStatus(%d)", int(s))
+}
+
+// TxResult 跟踪结果，Receipt 为最后一次看到的回执，交易未打包时为空；
+// TxAbandoned 时 Err 说明放弃的原因
+type TxResult struct {
+	Status  TxStatus
+	Receipt *types.Receipt
+	Err     error
+}
+
+// TrackEvent 跟踪过程中的事件
+type TrackEvent int
+
+const (
+	EventMined       TrackEvent = iota // 交易被打包，区块重组后重新打包也会触发
+	EventReorged                       // 打包交易的区块被重组，交易回到交易池
+	EventRebroadcast                   // 交易不在交易池中，已重新广播
+	EventError                         // 查询节点出错，会继续重试
+)
+
+// TrackOptions 跟踪交易的选项，零值表示一个确认、不超时、每 3 秒查询一次、最多重新广播 5 次
+type TrackOptions struct {
+	Confirmations   uint64
+	Timeout         time.Duration
+	Interval        time.Duration
+	MaxRebroadcasts int
+	// OnEvent 在跟踪过程中回调：EventMined、EventReorged 时 rp 为对应的回执，
+	// EventRebroadcast、EventError 时 err 为出错原因
+	OnEvent func(ev TrackEvent, rp *types.Receipt, err error)
+}
+
+// txSender 解析已签名交易的发送方
+func txSender(tx *types.Transaction) (common.Address, error) {
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.LatestSignerForChainID(tx.ChainId())
+	}
+	return types.Sender(signer, tx)
+}
+
+// missingRounds 连续多少次在交易池中找不到交易时重新广播
+const missingRounds = 3
+
+// defaultRebroadcasts 默认最多重新广播的次数，之后交易仍不在交易池中时放弃跟踪
+const defaultRebroadcasts = 5
+
+func rebroadcastLimit(n int) int {
+	if n <= 0 {
+		return defaultRebroadcasts
+	}
+	return n
+}
+
+// abandoned 放弃跟踪的结果，err 为节点拒绝重新广播的错误，为空时表示重新广播了 n 次
+func abandoned(n int, err error) *TxResult {
+	if err != nil {
+		err = fmt.Errorf("重新广播被节点拒绝：%s", err)
+	} else {
+		err = fmt.Errorf("重新广播 %d 次后交易仍不在交易池中", n)
+	}
+	return &TxResult{Status: TxAbandoned, Err: err}
+}
+
+// TrackTx 等待交易打包并达到确认数。打包的区块被重组掉后继续等待；
+// 交易不在交易池中时重新广播，Nonce 已被其他交易占用时返回 TxDropped；
+// 重新广播 MaxRebroadcasts 次仍找不到交易或被节点拒绝时返回 TxAbandoned。
+// 超时返回 TxTimeout，ctx 被取消时返回错误。
+func TrackTx(ctx context.Context, client Backend, tx *types.Transaction, o TrackOptions) (*TxResult, error) {
+	from, err := txSender(tx)
+	if err != nil {
+		return nil, fmt.Errorf("解析交易发送方失败：%s", err)
+	}
+	depth := o.Confirmations
+	if depth < 1 {
+		depth = 1
+	}
+	interval := o.Interval
+	if interval <= 0 {
+		interval = time.Second * 3
+	}
+	notify := func(ev TrackEvent, rp *types.Receipt, err error) {
+		if o.OnEvent != nil {
+			o.OnEvent(ev, rp, err)
+		}
+	}
+	var deadline <-chan time.Time
+	if o.Timeout > 0 {
+		timer := time.NewTimer(o.Timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	limit := rebroadcastLimit(o.MaxRebroadcasts)
+	var mined *types.Receipt
+	var missing, rebroadcasts int
+	for {
+		rp, err := client.TransactionReceipt(ctx, tx.Hash())
+		switch {
+		case rp != nil:
+			missing = 0
+			if mined == nil || mined.BlockHash != rp.BlockHash {
+				mined = rp
+				notify(EventMined, rp, nil)
+			}
+			head, err := client.HeaderByNumber(ctx, nil)
+			if err != nil {
+				notify(EventError, nil, err)
+				break
+			}
+			if head.Number.Uint64()+1 >= rp.BlockNumber.Uint64()+depth {
+				// 再确认一次回执仍在同一个区块，避免在重组过程中误判
+				again, err := client.TransactionReceipt(ctx, tx.Hash())
+				if err == nil && again.BlockHash == rp.BlockHash {
+					if rp.Status != types.ReceiptStatusSuccessful {
+						return &TxResult{Status: TxReverted, Receipt: rp}, nil
+					}
+					return &TxResult{Status: TxSuccess, Receipt: rp}, nil
+				}
+			}
+		case err == ethereum.NotFound:
+			if mined != nil {
+				notify(EventReorged, mined, nil)
+				mined = nil
+			}
+			if _, _, err := client.TransactionByHash(ctx, tx.Hash()); err == ethereum.NotFound {
+				missing++
+			} else {
+				missing = 0
+			}
+			if missing < missingRounds {
+				break
+			}
+			missing = 0
+			nonce, err := client.NonceAt(ctx, from, nil)
+			if err != nil {
+				notify(EventError, nil, err)
+				break
+			}
+			if nonce > tx.Nonce() {
+				// Nonce 已被其他交易占用，再确认一次回执后按丢弃处理
+				if rp, _ := client.TransactionReceipt(ctx, tx.Hash()); rp == nil {
+					return &TxResult{Status: TxDropped}, nil
+				}
+				break
+			}
+			if rebroadcasts >= limit {
+				return abandoned(rebroadcasts, nil), nil
+			}
+			err = client.SendTransaction(ctx, tx)
+			notify(EventRebroadcast, nil, err)
+			if IsRejected(err) {
+				return abandoned(rebroadcasts, err), nil
+			}
+			rebroadcasts++
+		default:
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			notify(EventError, nil, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline:
+			return &TxResult{Status: TxTimeout, Receipt: mined}, nil
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package ethutil
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/naiba/eth-tools/internal/testchain"
+)
+
+// signTransfer 由模拟链的第一个账户签名一笔转账，tip 不同的交易可以互相替换
+func signTransfer(t *testing.T, c *testchain.Chain, nonce uint64, tip int64) *types.Transaction {
+	t.Helper()
+	tx, err := types.SignNewTx(c.Keys[0], types.LatestSignerForChainID(big.NewInt(testchain.ChainID)), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(testchain.ChainID),
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(tip * params.GWei),
+		GasFeeCap: big.NewInt(100 * params.GWei),
+		Gas:       21000,
+		To:        &common.Address{0x1},
+		Value:     big.NewInt(1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// trackEvents 记录 TrackTx 的事件，wait 等待某个事件出现
+type trackEvents struct {
+	mu     sync.Mutex
+	events []TrackEvent
+}
+
+func (r *trackEvents) on(ev TrackEvent, rp *types.Receipt, err error) {
+	r.mu.Lock()
+	r.events = append(r.events, ev)
+	r.mu.Unlock()
+}
+
+func (r *trackEvents) count(ev TrackEvent) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int
+	for _, e := range r.events {
+		if e == ev {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *trackEvents) wait(t *testing.T, ev TrackEvent, n int) {
+	t.Helper()
+	for deadline := time.Now().Add(10 * time.Second); r.count(ev) < n; time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待第 %d 个事件 %d 超时，已有 %d 个", n, ev, r.count(ev))
+		}
+	}
+}
+
+// trackAsync 在后台跟踪交易，返回接收结果的通道
+func trackAsync(client Backend, tx *types.Transaction, o TrackOptions) <-chan *TxResult {
+	ch := make(chan *TxResult, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+		res, err := TrackTx(ctx, client, tx, o)
+		if err != nil {
+			res = &TxResult{Status: -1, Err: err}
+		}
+		ch <- res
+	}()
+	return ch
+}
+
+func TestTrackTxReorg(t *testing.T) {
+	c := testchain.New(t, 1)
+	ctx := context.Background()
+	genesis, err := c.HeaderByNumber(ctx, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := signTransfer(t, c, 0, 1)
+	if err := c.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	first := c.Mine(t, tx)
+
+	rec := &trackEvents{}
+	done := trackAsync(c, tx, TrackOptions{Confirmations: 3, Interval: 5 * time.Millisecond, OnEvent: rec.on})
+	rec.wait(t, EventMined, 1)
+
+	// 从创世块分叉出更长的链，打包交易的区块被重组掉
+	if err := c.Fork(ctx, genesis.Hash()); err != nil {
+		t.Fatal(err)
+	}
+	c.Commit()
+	c.Commit()
+	rec.wait(t, EventReorged, 1)
+	// 交易不在新链上，也不在交易池中，重新广播后再次打包
+	rec.wait(t, EventRebroadcast, 1)
+	for i := 0; i < 3; i++ {
+		c.Commit()
+	}
+	res := <-done
+	if res.Status != TxSuccess || res.Receipt == nil {
+		t.Fatalf("跟踪结果 %s，%v，预期成功", res.Status, res.Err)
+	}
+	if res.Receipt.BlockHash == first.BlockHash || res.Receipt.BlockNumber.Uint64() != 3 {
+		t.Errorf("回执在区块 %d，预期重组后在区块 3 重新打包", res.Receipt.BlockNumber)
+	}
+	if n := rec.count(EventMined); n != 2 {
+		t.Errorf("打包事件 %d 次，预期 2 次", n)
+	}
+}
+
+func TestTrackTxDropped(t *testing.T) {
+	c := testchain.New(t, 1)
+	ctx := context.Background()
+	// 同一 Nonce 的另一笔交易先打包，原交易永远不会上链
+	tx := signTransfer(t, c, 0, 1)
+	replacement := signTransfer(t, c, 0, 2)
+	if err := c.SendTransaction(ctx, replacement); err != nil {
+		t.Fatal(err)
+	}
+	c.Mine(t, replacement)
+
+	rec := &trackEvents{}
+	res, err := TrackTx(ctx, c, tx, TrackOptions{Interval: time.Millisecond, OnEvent: rec.on})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != TxDropped {
+		t.Fatalf("跟踪结果 %s，预期已丢弃", res.Status)
+	}
+	if n := rec.count(EventRebroadcast); n != 0 {
+		t.Errorf("重新广播 %d 次，Nonce 已被占用时不应重新广播", n)
+	}
+}
+
+func TestTrackTxTimeout(t *testing.T) {
+	c := testchain.New(t, 1)
+	ctx := context.Background()
+	tx := signTransfer(t, c, 0, 1)
+	if err := c.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	// 交易一直在交易池中
+	res, err := TrackTx(ctx, c, tx, TrackOptions{Timeout: 50 * time.Millisecond, Interval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != TxTimeout || res.Receipt != nil {
+		t.Fatalf("跟踪结果 %s，回执 %v，预期超时且没有回执", res.Status, res.Receipt)
+	}
+
+	// 已经打包但确认数不够，超时结果带上最后看到的回执
+	c.Mine(t, tx)
+	res, err = TrackTx(ctx, c, tx, TrackOptions{Confirmations: 5, Timeout: 50 * time.Millisecond, Interval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != TxTimeout || res.Receipt == nil {
+		t.Fatalf("跟踪结果 %s，回执 %v，预期超时并带有回执", res.Status, res.Receipt)
+	}
+}
+
+// dropBackend 广播交易时不交给模拟链，返回 err
+type dropBackend struct {
+	*testchain.Chain
+	err error
+}
+
+func (b *dropBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return b.err
+}
+
+func TestTrackTxAbandoned(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		rebroadcasts int // 预期重新广播的次数
+	}{
+		{"节点接受但交易不在交易池中", nil, 2},
+		{"节点拒绝", errors.New("insufficient funds for gas * price + value"), 1},
+		{"网络错误时继续重试", errors.New("connection reset by peer"), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testchain.New(t, 1)
+			tx := signTransfer(t, c, 0, 1)
+			rec := &trackEvents{}
+			res, err := TrackTx(context.Background(), &dropBackend{Chain: c, err: tt.err}, tx, TrackOptions{Interval: time.Millisecond, MaxRebroadcasts: 2, OnEvent: rec.on})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Status != TxAbandoned || res.Err == nil {
+				t.Fatalf("跟踪结果 %s，%v，预期放弃跟踪", res.Status, res.Err)
+			}
+			if n := rec.count(EventRebroadcast); n != tt.rebroadcasts {
+				t.Errorf("重新广播 %d 次，预期 %d 次", n, tt.rebroadcasts)
+			}
+		})
+	}
+}
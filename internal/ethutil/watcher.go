@@ -0,0 +1,483 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// batchLimit 每次批量请求最多包含的调用数
+const batchLimit = 100
+
+// WatcherOptions 共享跟踪器的选项，零值表示一个确认、不超时、轮询间隔 3 秒、最多重新广播 5 次
+type WatcherOptions struct {
+	Confirmations   uint64
+	Timeout         time.Duration // 每笔交易从开始跟踪算起的最长等待时间
+	Interval        time.Duration // 不支持订阅时的轮询间隔
+	MaxRebroadcasts int           // 交易不在交易池中时最多重新广播的次数，之后放弃跟踪
+}
+
+// Watcher 多笔交易共用的确认跟踪器：通过 websocket 订阅新区块，
+// 每个区块批量查询所有交易的回执；节点不支持订阅（如 HTTP）时改为定时轮询
+type Watcher struct {
+	rc     *rpc.Client // 为空时逐笔查询，不使用批量请求
+	client Backend
+	o      WatcherOptions
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	txs     map[common.Hash]*watched
+	polling bool
+	closed  bool
+}
+
+type watched struct {
+	tx       *types.Transaction
+	from     common.Address
+	onEvent  func(ev TrackEvent, rp *types.Receipt, err error)
+	result   chan *TxResult
+	deadline time.Time
+	mined    *types.Receipt
+	missing  int
+	// rebroadcasts 已重新广播的次数
+	rebroadcasts int
+}
+
+// NewWatcher 创建跟踪器并立即订阅新区块，订阅失败时使用轮询
+func NewWatcher(rc *rpc.Client, o WatcherOptions) *Watcher {
+	return newWatcher(rc, ethclient.NewClient(rc), o)
+}
+
+// NewBackendWatcher 在没有 RPC 连接的节点接口（如测试中的模拟链）上创建跟踪器，逐笔查询交易状态
+func NewBackendWatcher(client Backend, o WatcherOptions) *Watcher {
+	return newWatcher(nil, client, o)
+}
+
+func newWatcher(rc *rpc.Client, client Backend, o WatcherOptions) *Watcher {
+	if o.Confirmations < 1 {
+		o.Confirmations = 1
+	}
+	if o.Interval <= 0 {
+		o.Interval = time.Second * 3
+	}
+	o.MaxRebroadcasts = rebroadcastLimit(o.MaxRebroadcasts)
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Watcher{
+		rc:     rc,
+		client: client,
+		o:      o,
+		cancel: cancel,
+		txs:    make(map[common.Hash]*watched),
+	}
+	heads := make(chan *types.Header, 16)
+	sub, err := w.client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		sub = nil
+	}
+	w.polling = sub == nil
+	go w.run(ctx, heads, sub)
+	return w
+}
+
+// Polling 是否在使用轮询，websocket 断开后也会切换到轮询
+func (w *Watcher) Polling() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.polling
+}
+
+// Watch 开始跟踪一笔已广播的交易，结果从返回的通道读取；
+// onEvent 可以为空，在跟踪器的协程中回调。Watcher 关闭时未完成的通道会被关闭。
+func (w *Watcher) Watch(tx *types.Transaction, onEvent func(ev TrackEvent, rp *types.Receipt, err error)) (<-chan *TxResult, error) {
+	from, err := txSender(tx)
+	if err != nil {
+		return nil, fmt.Errorf("解析交易发送方失败：%s", err)
+	}
+	item := &watched{
+		tx:      tx,
+		from:    from,
+		onEvent: onEvent,
+		result:  make(chan *TxResult, 1),
+	}
+	if w.o.Timeout > 0 {
+		item.deadline = time.Now().Add(w.o.Timeout)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil, fmt.Errorf("跟踪器已关闭")
+	}
+	w.txs[tx.Hash()] = item
+	return item.result, nil
+}
+
+// Close 停止跟踪，未完成的通道会被关闭
+func (w *Watcher) Close() {
+	w.cancel()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	w.closed = true
+	for hash, item := range w.txs {
+		close(item.result)
+		delete(w.txs, hash)
+	}
+}
+
+func (w *Watcher) run(ctx context.Context, heads chan *types.Header, sub ethereum.Subscription) {
+	ticker := time.NewTicker(w.o.Interval)
+	defer ticker.Stop()
+	var subErr <-chan error
+	if sub != nil {
+		subErr = sub.Err()
+		defer func() {
+			if sub != nil {
+				sub.Unsubscribe()
+			}
+		}()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case head := <-heads:
+			// 处理积压时只看最新的区块
+			for len(heads) > 0 {
+				head = <-heads
+			}
+			w.check(ctx, head)
+		case <-subErr:
+			sub, subErr = nil, nil
+			w.setPolling(true)
+		case <-ticker.C:
+			if sub == nil {
+				// 定时尝试重新订阅
+				if s, err := w.client.SubscribeNewHead(ctx, heads); err == nil {
+					sub, subErr = s, s.Err()
+					w.setPolling(false)
+				}
+			}
+			// 订阅模式下只在需要判断超时时才定时检查
+			if sub == nil || w.o.Timeout > 0 {
+				w.check(ctx, nil)
+			}
+		}
+	}
+}
+
+func (w *Watcher) setPolling(polling bool) {
+	w.mu.Lock()
+	w.polling = polling
+	w.mu.Unlock()
+}
+
+// check 批量查询所有交易的回执，head 为空时先获取最新区块；查询出错时仍然判断超时
+func (w *Watcher) check(ctx context.Context, head *types.Header) {
+	w.mu.Lock()
+	items := make([]*watched, 0, len(w.txs))
+	for _, item := range w.txs {
+		items = append(items, item)
+	}
+	w.mu.Unlock()
+	if len(items) == 0 {
+		return
+	}
+	w.inspect(ctx, head, items)
+
+	now := time.Now()
+	for _, item := range items {
+		if !item.deadline.IsZero() && now.After(item.deadline) {
+			w.finish(item, &TxResult{Status: TxTimeout, Receipt: item.mined})
+		}
+	}
+}
+
+// inspect 查询交易的回执，达到确认数的交易再确认一次回执后结束跟踪
+func (w *Watcher) inspect(ctx context.Context, head *types.Header, items []*watched) {
+	if head == nil {
+		var err error
+		if head, err = w.client.HeaderByNumber(ctx, nil); err != nil {
+			w.notifyAll(items, err)
+			return
+		}
+	}
+
+	receipts, errs, err := w.receipts(ctx, items)
+	if err != nil {
+		w.notifyAll(items, err)
+		return
+	}
+
+	var missing, ready []*watched
+	for i, item := range items {
+		rp := receipts[i]
+		if errs[i] != nil {
+			item.notify(EventError, nil, errs[i])
+			continue
+		}
+		if rp == nil {
+			if item.mined != nil {
+				item.notify(EventReorged, item.mined, nil)
+				item.mined = nil
+			}
+			missing = append(missing, item)
+			continue
+		}
+		item.missing = 0
+		if item.mined == nil || item.mined.BlockHash != rp.BlockHash {
+			item.mined = rp
+			item.notify(EventMined, rp, nil)
+		}
+		if head.Number.Uint64()+1 >= rp.BlockNumber.Uint64()+w.o.Confirmations {
+			ready = append(ready, item)
+		}
+	}
+	w.confirm(ctx, ready)
+	w.checkMissing(ctx, missing)
+}
+
+// confirm 再查询一次达到确认数的交易的回执，仍在同一个区块时才结束跟踪，避免在重组过程中误判；
+// 回执变化的交易留到下一轮处理
+func (w *Watcher) confirm(ctx context.Context, items []*watched) {
+	if len(items) == 0 {
+		return
+	}
+	receipts, errs, err := w.receipts(ctx, items)
+	if err != nil {
+		w.notifyAll(items, err)
+		return
+	}
+	for i, item := range items {
+		rp := receipts[i]
+		if errs[i] != nil {
+			item.notify(EventError, nil, errs[i])
+			continue
+		}
+		if rp == nil || rp.BlockHash != item.mined.BlockHash {
+			continue
+		}
+		status := TxSuccess
+		if rp.Status != types.ReceiptStatusSuccessful {
+			status = TxReverted
+		}
+		w.finish(item, &TxResult{Status: status, Receipt: rp})
+	}
+}
+
+// checkMissing 处理还没有回执的交易：不在交易池中时重新广播，Nonce 已被占用时按丢弃处理，
+// 重新广播次数用完或被节点拒绝时放弃跟踪
+func (w *Watcher) checkMissing(ctx context.Context, items []*watched) {
+	if len(items) == 0 {
+		return
+	}
+	pooled, errs, err := w.pooled(ctx, items)
+	if err != nil {
+		w.notifyAll(items, err)
+		return
+	}
+	var stale []*watched
+	for i, item := range items {
+		if errs[i] != nil {
+			item.notify(EventError, nil, errs[i])
+			continue
+		}
+		if pooled[i] {
+			item.missing = 0
+			continue
+		}
+		item.missing++
+		if item.missing >= missingRounds {
+			item.missing = 0
+			stale = append(stale, item)
+		}
+	}
+	if len(stale) == 0 {
+		return
+	}
+
+	// 每个发送方只查一次 Nonce
+	var senders []common.Address
+	seen := make(map[common.Address]bool)
+	for _, item := range stale {
+		if !seen[item.from] {
+			seen[item.from] = true
+			senders = append(senders, item.from)
+		}
+	}
+	counts, errs, err := w.nonces(ctx, senders)
+	if err != nil {
+		w.notifyAll(stale, err)
+		return
+	}
+	nonces := make(map[common.Address]uint64)
+	failed := make(map[common.Address]error)
+	for i, from := range senders {
+		nonces[from] = counts[i]
+		if errs[i] != nil {
+			failed[from] = errs[i]
+		}
+	}
+	for _, item := range stale {
+		if err := failed[item.from]; err != nil {
+			item.notify(EventError, nil, err)
+			continue
+		}
+		if nonces[item.from] > item.tx.Nonce() {
+			// 回执可能在两次查询之间出现，留到下一个区块再确认
+			if rp, _ := w.client.TransactionReceipt(ctx, item.tx.Hash()); rp == nil {
+				w.finish(item, &TxResult{Status: TxDropped})
+			}
+			continue
+		}
+		if item.rebroadcasts >= w.o.MaxRebroadcasts {
+			w.finish(item, abandoned(item.rebroadcasts, nil))
+			continue
+		}
+		err := w.client.SendTransaction(ctx, item.tx)
+		item.notify(EventRebroadcast, nil, err)
+		if IsRejected(err) {
+			w.finish(item, abandoned(item.rebroadcasts, err))
+			continue
+		}
+		item.rebroadcasts++
+	}
+}
+
+// receipts 查询交易的回执，还没有回执时为 nil；errs 为每笔交易单独的查询错误
+func (w *Watcher) receipts(ctx context.Context, items []*watched) ([]*types.Receipt, []error, error) {
+	receipts := make([]*types.Receipt, len(items))
+	errs := make([]error, len(items))
+	if w.rc == nil {
+		for i, item := range items {
+			rp, err := w.client.TransactionReceipt(ctx, item.tx.Hash())
+			if err != nil && err != ethereum.NotFound {
+				errs[i] = err
+			}
+			receipts[i] = rp
+		}
+		return receipts, errs, nil
+	}
+	elems := make([]rpc.BatchElem, len(items))
+	for i, item := range items {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{item.tx.Hash()},
+			Result: &receipts[i],
+		}
+	}
+	if err := w.batch(ctx, elems); err != nil {
+		return nil, nil, err
+	}
+	for i := range elems {
+		errs[i] = elems[i].Error
+	}
+	return receipts, errs, nil
+}
+
+// pooled 节点中是否还能查到交易（在交易池中或已打包）
+func (w *Watcher) pooled(ctx context.Context, items []*watched) ([]bool, []error, error) {
+	found := make([]bool, len(items))
+	errs := make([]error, len(items))
+	if w.rc == nil {
+		for i, item := range items {
+			_, _, err := w.client.TransactionByHash(ctx, item.tx.Hash())
+			if err != nil && err != ethereum.NotFound {
+				errs[i] = err
+			}
+			found[i] = err == nil
+		}
+		return found, errs, nil
+	}
+	raw := make([]*json.RawMessage, len(items))
+	elems := make([]rpc.BatchElem, len(items))
+	for i, item := range items {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getTransactionByHash",
+			Args:   []interface{}{item.tx.Hash()},
+			Result: &raw[i],
+		}
+	}
+	if err := w.batch(ctx, elems); err != nil {
+		return nil, nil, err
+	}
+	for i := range elems {
+		errs[i] = elems[i].Error
+		found[i] = raw[i] != nil && string(*raw[i]) != "null"
+	}
+	return found, errs, nil
+}
+
+// nonces 查询发送方在最新区块的 Nonce
+func (w *Watcher) nonces(ctx context.Context, senders []common.Address) ([]uint64, []error, error) {
+	nonces := make([]uint64, len(senders))
+	errs := make([]error, len(senders))
+	if w.rc == nil {
+		for i, from := range senders {
+			nonces[i], errs[i] = w.client.NonceAt(ctx, from, nil)
+		}
+		return nonces, errs, nil
+	}
+	counts := make([]hexutil.Uint64, len(senders))
+	elems := make([]rpc.BatchElem, len(senders))
+	for i, from := range senders {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getTransactionCount",
+			Args:   []interface{}{from, "latest"},
+			Result: &counts[i],
+		}
+	}
+	if err := w.batch(ctx, elems); err != nil {
+		return nil, nil, err
+	}
+	for i := range elems {
+		nonces[i], errs[i] = uint64(counts[i]), elems[i].Error
+	}
+	return nonces, errs, nil
+}
+
+// batch 分批发送批量请求
+func (w *Watcher) batch(ctx context.Context, elems []rpc.BatchElem) error {
+	for start := 0; start < len(elems); start += batchLimit {
+		end := start + batchLimit
+		if end > len(elems) {
+			end = len(elems)
+		}
+		if err := w.rc.BatchCallContext(ctx, elems[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Watcher) notifyAll(items []*watched, err error) {
+	for _, item := range items {
+		item.notify(EventError, nil, err)
+	}
+}
+
+func (w *Watcher) finish(item *watched, res *TxResult) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.txs[item.tx.Hash()] != item {
+		return
+	}
+	delete(w.txs, item.tx.Hash())
+	item.result <- res
+}
+
+func (item *watched) notify(ev TrackEvent, rp *types.Receipt, err error) {
+	if item.onEvent != nil {
+		item.onEvent(ev, rp, err)
+	}
+}
@@ -0,0 +1,142 @@
+package ethutil
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/naiba/eth-tools/internal/testchain"
+)
+
+// faultBackend 按设置让模拟链的部分查询出错，不支持订阅，跟踪器只能轮询
+type faultBackend struct {
+	*testchain.Chain
+
+	mu       sync.Mutex
+	headErr  error // HeaderByNumber 返回的错误
+	receipts int   // 已查询回执的次数
+	hideFrom int   // 从第几次查询起回执消失，0 表示不消失
+}
+
+func (b *faultBackend) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return nil, errors.New("notifications not supported")
+}
+
+func (b *faultBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	b.mu.Lock()
+	err := b.headErr
+	b.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return b.Chain.HeaderByNumber(ctx, number)
+}
+
+func (b *faultBackend) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	b.mu.Lock()
+	b.receipts++
+	hide := b.hideFrom > 0 && b.receipts >= b.hideFrom
+	b.mu.Unlock()
+	if hide {
+		return nil, ethereum.NotFound
+	}
+	return b.Chain.TransactionReceipt(ctx, hash)
+}
+
+// waitResult 读取跟踪结果，通道被关闭时返回 nil
+func waitResult(t *testing.T, ch <-chan *TxResult) *TxResult {
+	t.Helper()
+	select {
+	case res := <-ch:
+		return res
+	case <-time.After(10 * time.Second):
+		t.Fatal("等待跟踪结果超时")
+		return nil
+	}
+}
+
+func TestWatcherConfirmations(t *testing.T) {
+	c := testchain.New(t, 1)
+	ctx := context.Background()
+	w := NewBackendWatcher(c, WatcherOptions{Confirmations: 3, Interval: 5 * time.Millisecond})
+	defer w.Close()
+
+	tx := signTransfer(t, c, 0, 1)
+	if err := c.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	rec := &trackEvents{}
+	ch, err := w.Watch(tx, rec.on)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Mine(t, tx)
+	rec.wait(t, EventMined, 1)
+	select {
+	case res := <-ch:
+		t.Fatalf("确认数不够时结束跟踪：%s", res.Status)
+	case <-time.After(50 * time.Millisecond):
+	}
+	c.Commit()
+	c.Commit()
+	if res := waitResult(t, ch); res == nil || res.Status != TxSuccess || res.Receipt.BlockNumber.Uint64() != 1 {
+		t.Fatalf("跟踪结果 %v，预期在区块 1 打包成功", res)
+	}
+}
+
+func TestWatcherTimeoutOnNodeErrors(t *testing.T) {
+	c := testchain.New(t, 1)
+	b := &faultBackend{Chain: c, headErr: errors.New("connection refused")}
+	w := NewBackendWatcher(b, WatcherOptions{Timeout: 50 * time.Millisecond, Interval: 5 * time.Millisecond})
+	defer w.Close()
+	if !w.Polling() {
+		t.Fatal("不支持订阅时应当轮询")
+	}
+
+	tx := signTransfer(t, c, 0, 1)
+	rec := &trackEvents{}
+	ch, err := w.Watch(tx, rec.on)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 节点一直出错时也要按时结束
+	if res := waitResult(t, ch); res == nil || res.Status != TxTimeout {
+		t.Fatalf("跟踪结果 %v，预期超时", res)
+	}
+	if rec.count(EventError) == 0 {
+		t.Error("节点出错时应当通知 EventError")
+	}
+}
+
+func TestWatcherRechecksReceipt(t *testing.T) {
+	c := testchain.New(t, 1)
+	ctx := context.Background()
+	tx := signTransfer(t, c, 0, 1)
+	if err := c.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	c.Mine(t, tx)
+
+	// 第一次查到回执后回执就消失了（交易被重组掉），不能按第一次的回执记为成功
+	b := &faultBackend{Chain: c, hideFrom: 2}
+	w := NewBackendWatcher(b, WatcherOptions{Timeout: 100 * time.Millisecond, Interval: 5 * time.Millisecond})
+	defer w.Close()
+	rec := &trackEvents{}
+	ch, err := w.Watch(tx, rec.on)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := waitResult(t, ch)
+	if res == nil || res.Status != TxTimeout || res.Receipt != nil {
+		t.Fatalf("跟踪结果 %v，预期超时且没有回执", res)
+	}
+	if rec.count(EventMined) != 1 || rec.count(EventReorged) != 1 {
+		t.Errorf("打包事件 %d 次，重组事件 %d 次，预期各 1 次", rec.count(EventMined), rec.count(EventReorged))
+	}
+}
@@ -0,0 +1,334 @@
+// Package faucet 测试网水龙头：向指定钱包发送 ETH 或铸造代币（调用代币的 addToken，
+// 不能铸造时从水龙头的库存转账），不依赖界面，桌面工具、命令行与 HTTP 水龙头共用
+package faucet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/naiba/eth-tools/internal/erc20"
+	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/netconf"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// MainnetChainID 主网不发放 ETH
+const MainnetChainID = 1
+
+// ErrorCode 领取失败的原因分类，HTTP 接口原样返回
+type ErrorCode string
+
+const (
+	CodeInvalid     ErrorCode = "invalid"     // 请求参数有误
+	CodeRefused     ErrorCode = "refused"     // 该网络不允许领取
+	CodeChain       ErrorCode = "chain"       // 节点不是预期的链
+	CodeSend        ErrorCode = "send"        // 签名或广播失败
+	CodeReverted    ErrorCode = "reverted"    // 交易执行失败
+	CodeUnconfirmed ErrorCode = "unconfirmed" // 交易被丢弃、等待超时或跟踪失败，结果未知
+)
+
+// Error 领取失败，Tx 为已广播的交易（没有广播时为空）
+type Error struct {
+	Code   ErrorCode
+	Tx     common.Hash
+	Reason string // 交易执行失败的原因
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("%s，失败原因：%s", e.Err, e.Reason)
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Request 一次领取请求
+type Request struct {
+	To     common.Address
+	Token  common.Address // 零地址表示领取 ETH
+	Amount *big.Int       // 最小单位：ETH 为 wei，代币为代币的最小单位
+}
+
+// ETH 是否领取 ETH
+func (r Request) ETH() bool {
+	return r.Token == (common.Address{})
+}
+
+// Result 领取成功的结果
+type Result struct {
+	Tx      common.Hash
+	Link    string // 区块浏览器中的交易链接
+	Block   uint64
+	GasUsed uint64
+	Balance *big.Int // 领取后钱包的余额（最小单位）
+}
+
+// Config 水龙头的网络、发放账户与交易设置
+type Config struct {
+	Network netconf.Profile
+	Signer  ethutil.Signer
+	Fee     ethutil.FeeConfig
+	Timeout time.Duration // 每笔交易等待确认的最长时间，默认 5 分钟
+}
+
+// Faucet 在一个节点连接上发放 ETH 与代币，可以并发调用
+type Faucet struct {
+	network netconf.Profile
+	client  *ethclient.Client
+	chainID *big.Int
+	signer  ethutil.Signer
+	fee     ethutil.FeeConfig
+	timeout time.Duration
+
+	// send 串行化签名与广播，同一个账户的交易不会拿到相同的 Nonce
+	send sync.Mutex
+	// nonces 本地分配的 Nonce，第一次发送时创建，节点因 Nonce 拒绝交易后重新同步
+	nonces *ethutil.NonceManager
+}
+
+// New 核对节点的链 ID 并创建水龙头，rc 在 Close 时关闭
+func New(ctx context.Context, rc *rpc.Client, cfg Config) (*Faucet, error) {
+	client := ethclient.NewClient(rc)
+	chainID := new(big.Int).SetUint64(cfg.Network.ChainID)
+	if err := ethutil.VerifyChainID(ctx, client, chainID); err != nil {
+		client.Close()
+		return nil, chainError(err)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = time.Minute * 5
+	}
+	return &Faucet{
+		network: cfg.Network,
+		client:  client,
+		chainID: chainID,
+		signer:  cfg.Signer,
+		fee:     cfg.Fee,
+		timeout: cfg.Timeout,
+	}, nil
+}
+
+// Close 断开节点
+func (f *Faucet) Close() {
+	f.client.Close()
+}
+
+// Address 发放账户的地址
+func (f *Faucet) Address() common.Address {
+	return f.signer.Address()
+}
+
+// Network 水龙头所在的网络
+func (f *Faucet) Network() netconf.Profile {
+	return f.network
+}
+
+// Client 水龙头使用的节点连接
+func (f *Faucet) Client() *ethclient.Client {
+	return f.client
+}
+
+func chainError(err error) error {
+	var mismatch *ethutil.ChainMismatchError
+	if errors.As(err, &mismatch) {
+		return &Error{Code: CodeChain, Err: err}
+	}
+	return err
+}
+
+// Dispense 发放 ETH 或铸造代币并等待交易确认，失败时返回 *Error
+func (f *Faucet) Dispense(ctx context.Context, req Request) (*Result, error) {
+	tx, err := f.Send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return f.Confirm(ctx, req, tx)
+}
+
+// Send 签名并广播领取交易，不等待确认，失败时返回 *Error。
+// 签名与广播是串行的，并发调用时交易按调用顺序使用连续的 Nonce。
+func (f *Faucet) Send(ctx context.Context, req Request) (*types.Transaction, error) {
+	if req.To == (common.Address{}) {
+		return nil, &Error{Code: CodeInvalid, Err: errors.New("钱包地址有误")}
+	}
+	if req.Amount == nil || req.Amount.Sign() <= 0 {
+		return nil, &Error{Code: CodeInvalid, Err: errors.New("领取数量有误")}
+	}
+	if req.ETH() && f.network.ChainID == MainnetChainID {
+		return nil, &Error{Code: CodeRefused, Err: errors.New("此网络无法领取 ETH")}
+	}
+	f.send.Lock()
+	defer f.send.Unlock()
+	if f.nonces == nil {
+		nonces, err := ethutil.NewNonceManager(ctx, f.client, f.signer.Address())
+		if err != nil {
+			return nil, &Error{Code: CodeSend, Err: fmt.Errorf("获取钱包 Nonce 失败：%s", err)}
+		}
+		f.nonces = nonces
+	}
+	nonce := f.nonces.Next()
+	o := ethutil.TxOptions{Fee: f.fee, ChainID: f.chainID, Nonce: &nonce}
+	if req.ETH() {
+		o.Value = req.Amount
+	}
+	opts, err := ethutil.NewTransactOpts(ctx, f.client, f.signer, o)
+	if err != nil {
+		f.nonces.Release(nonce)
+		var mismatch *ethutil.ChainMismatchError
+		if errors.As(err, &mismatch) {
+			return nil, &Error{Code: CodeChain, Err: err}
+		}
+		return nil, &Error{Code: CodeSend, Err: err}
+	}
+	signed := false
+	sign := opts.Signer
+	opts.Signer = func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		tx, err := sign(addr, tx)
+		signed = err == nil
+		return tx, err
+	}
+	tx, err := f.sendTx(ctx, req, opts)
+	if err != nil {
+		var ferr *Error
+		if errors.As(err, &ferr) && ferr.Code == CodeSend && (signed || ethutil.IsNonceError(err)) {
+			// 签名后广播失败时交易可能已经进入交易池，Nonce 不能再分配给别的交易，
+			// 下次发送前按节点的 pending Nonce 重新同步
+			f.nonces = nil
+		} else {
+			f.nonces.Release(nonce)
+		}
+		return nil, err
+	}
+	return tx, nil
+}
+
+// sendTx 按已分配 Nonce 的交易参数签名并广播领取交易
+func (f *Faucet) sendTx(ctx context.Context, req Request, opts *bind.TransactOpts) (*types.Transaction, error) {
+	if req.ETH() {
+		tx, err := ethutil.SignTx(ctx, f.client, opts, f.chainID, req.To, nil)
+		if err == nil {
+			err = f.client.SendTransaction(ctx, tx)
+		}
+		if err != nil {
+			return nil, &Error{Code: CodeSend, Err: err}
+		}
+		return tx, nil
+	}
+	token, err := erc20.NewErc20(req.Token, f.client)
+	if err != nil {
+		return nil, &Error{Code: CodeInvalid, Err: fmt.Errorf("代币错误：%s", err)}
+	}
+	// 水龙头账户不能铸造时从库存中转账，库存由 TopUpper 补充
+	from := f.signer.Address()
+	if !f.canCall(ctx, from, req.Token, "addToken", req.To, req.Amount) {
+		balance, err := token.BalanceOf(&bind.CallOpts{Context: ctx}, from)
+		if err == nil && balance.Cmp(req.Amount) >= 0 {
+			tx, err := token.Transfer(opts, req.To, req.Amount)
+			if err != nil {
+				return nil, &Error{Code: CodeSend, Err: err}
+			}
+			return tx, nil
+		}
+	}
+	tx, err := token.AddToken(opts, req.To, req.Amount)
+	if err != nil {
+		return nil, &Error{Code: CodeSend, Reason: f.addTokenRevertReason(ctx, req), Err: err}
+	}
+	return tx, nil
+}
+
+// canCall 估算 from 调用代币合约方法是否能够成功
+func (f *Faucet) canCall(ctx context.Context, from, token common.Address, method string, args ...interface{}) bool {
+	data, err := revertABIs[0].Pack(method, args...)
+	if err != nil {
+		return false
+	}
+	_, err = f.client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &token, Data: data})
+	return err == nil
+}
+
+// Confirm 等待 Send 广播的交易确认，失败时返回 *Error
+func (f *Faucet) Confirm(ctx context.Context, req Request, tx *types.Transaction) (*Result, error) {
+	res, err := ethutil.TrackTx(ctx, f.client, tx, ethutil.TrackOptions{Timeout: f.timeout})
+	if err != nil {
+		return nil, &Error{Code: CodeUnconfirmed, Tx: tx.Hash(), Err: fmt.Errorf("跟踪交易失败：%s", err)}
+	}
+	switch res.Status {
+	case ethutil.TxSuccess:
+	case ethutil.TxReverted:
+		reason, err := ethutil.RevertReason(ctx, f.client, tx, res.Receipt.BlockNumber, revertABIs...)
+		if err != nil {
+			reason = err.Error()
+		}
+		return nil, &Error{Code: CodeReverted, Tx: tx.Hash(), Reason: reason, Err: errors.New("交易执行失败")}
+	default:
+		return nil, &Error{Code: CodeUnconfirmed, Tx: tx.Hash(), Err: fmt.Errorf("交易%s", res.Status)}
+	}
+	result := &Result{
+		Tx:      tx.Hash(),
+		Link:    f.network.TxURL(tx.Hash().Hex()),
+		Block:   res.Receipt.BlockNumber.Uint64(),
+		GasUsed: res.Receipt.GasUsed,
+	}
+	// 余额只用于展示，查询失败不影响结果
+	result.Balance, _ = f.Balance(ctx, req.Token, req.To)
+	return result, nil
+}
+
+// Balance 查询钱包的 ETH（token 为零地址）或代币余额
+func (f *Faucet) Balance(ctx context.Context, token, addr common.Address) (*big.Int, error) {
+	if token == (common.Address{}) {
+		return f.client.BalanceAt(ctx, addr, nil)
+	}
+	erc, err := erc20.NewErc20(token, f.client)
+	if err != nil {
+		return nil, err
+	}
+	return erc.BalanceOf(&bind.CallOpts{Context: ctx}, addr)
+}
+
+// Decimals ETH（token 为零地址）或代币的小数位数，用于换算领取数量
+func (f *Faucet) Decimals(ctx context.Context, token common.Address) (int, error) {
+	if token == (common.Address{}) {
+		return units.Ether, nil
+	}
+	erc, err := erc20.NewErc20(token, f.client)
+	if err != nil {
+		return 0, err
+	}
+	d, err := erc.Decimals(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return 0, fmt.Errorf("获取代币小数位数失败：%s", err)
+	}
+	return int(d), nil
+}
+
+// revertABIs 解码自定义错误时使用的合约 ABI
+var revertABIs = ethutil.MustParseABIs(erc20.Erc20ABI)
+
+// addTokenRevertReason 模拟调用 addToken，返回失败原因，调用能够成功时返回空字符串
+func (f *Faucet) addTokenRevertReason(ctx context.Context, req Request) string {
+	data, err := revertABIs[0].Pack("addToken", req.To, req.Amount)
+	if err != nil {
+		return ""
+	}
+	from := f.signer.Address()
+	reason, err := ethutil.SimulateRevert(ctx, f.client, ethereum.CallMsg{From: from, To: &req.Token, Data: data}, nil, revertABIs...)
+	if err != nil {
+		return ""
+	}
+	return reason
+}
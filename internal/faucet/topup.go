@@ -0,0 +1,214 @@
+package faucet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/naiba/eth-tools/internal/erc20"
+	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// TopUpMethod 补充库存的方式
+type TopUpMethod string
+
+const (
+	TopUpTransfer TopUpMethod = "transfer" // 从国库账户转账
+	TopUpMint     TopUpMethod = "addToken" // 国库账户调用 addToken 铸造给水龙头
+	TopUpSet      TopUpMethod = "setToken" // 国库账户调用 setToken 直接设置水龙头的余额
+)
+
+// TopUpRule 一种资产的补充规则：水龙头的余额低于 Threshold 时补充到 Target
+type TopUpRule struct {
+	Token     common.Address // 零地址表示 ETH
+	Threshold *big.Int       // 最小单位
+	Target    *big.Int       // 最小单位
+}
+
+// TopUp 一次补充的记录，Err 不为空表示补充失败
+type TopUp struct {
+	Token   common.Address
+	Method  TopUpMethod
+	Balance *big.Int // 补充前水龙头的余额
+	Amount  *big.Int // 补充的数量
+	Tx      common.Hash
+	// Decimals 资产的小数位数，只用于展示，获取失败时为 0，按最小单位展示
+	Decimals int
+	Err      error
+}
+
+func (t TopUp) String() string {
+	asset := "ETH"
+	if t.Token != (common.Address{}) {
+		asset = "代币 " + t.Token.Hex()
+	}
+	if t.Err != nil {
+		return fmt.Sprintf("补充 %s 失败（余额 %s）：%s", asset, units.Format(t.Balance, t.Decimals), t.Err)
+	}
+	return fmt.Sprintf("已补充 %s：余额 %s，通过 %s 补充 %s：Transaction-%s", asset,
+		units.Format(t.Balance, t.Decimals), t.Method, units.Format(t.Amount, t.Decimals), t.Tx.Hex())
+}
+
+// TopUpper 监视水龙头账户的余额，低于阈值时从国库账户补充
+type TopUpper struct {
+	f        *Faucet
+	treasury ethutil.Signer
+	rules    []TopUpRule
+
+	// mu 串行化国库账户的交易
+	mu sync.Mutex
+}
+
+// NewTopUpper 创建补充器，国库账户不能是水龙头账户本身
+func (f *Faucet) NewTopUpper(treasury ethutil.Signer, rules []TopUpRule) (*TopUpper, error) {
+	if treasury.Address() == f.Address() {
+		return nil, errors.New("国库账户不能是水龙头账户")
+	}
+	for _, r := range rules {
+		if r.Threshold == nil || r.Target == nil || r.Threshold.Sign() < 0 || r.Target.Cmp(r.Threshold) <= 0 {
+			return nil, errors.New("补充规则有误：补充目标必须大于阈值")
+		}
+	}
+	return &TopUpper{f: f, treasury: treasury, rules: rules}, nil
+}
+
+// Treasury 国库账户的地址
+func (t *TopUpper) Treasury() common.Address {
+	return t.treasury.Address()
+}
+
+// Check 检查所有资产的余额，不足的补充到目标值并等待确认，返回本次进行的补充
+func (t *TopUpper) Check(ctx context.Context) []TopUp {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var list []TopUp
+	for _, r := range t.rules {
+		balance, err := t.f.Balance(ctx, r.Token, t.f.Address())
+		if err != nil {
+			list = append(list, TopUp{Token: r.Token, Err: fmt.Errorf("获取水龙头余额失败：%s", err)})
+			continue
+		}
+		if balance.Cmp(r.Threshold) >= 0 {
+			continue
+		}
+		list = append(list, t.topUp(ctx, r, balance))
+	}
+	return list
+}
+
+// Run 每隔 interval 检查一次，直到 ctx 取消，每次补充（包括失败的）都调用 onTopUp
+func (t *TopUpper) Run(ctx context.Context, interval time.Duration, onTopUp func(TopUp)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, tu := range t.Check(ctx) {
+			if ctx.Err() != nil {
+				return
+			}
+			onTopUp(tu)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *TopUpper) topUp(ctx context.Context, r TopUpRule, balance *big.Int) TopUp {
+	faucet := t.f.Address()
+	tu := TopUp{Token: r.Token, Method: TopUpTransfer, Balance: balance, Amount: new(big.Int).Sub(r.Target, balance)}
+	tu.Decimals, _ = t.f.Decimals(ctx, r.Token)
+	o := ethutil.TxOptions{Fee: t.f.fee, ChainID: t.f.chainID}
+	var tx *types.Transaction
+	if r.Token == (common.Address{}) {
+		o.Value = tu.Amount
+		opts, err := ethutil.NewTransactOpts(ctx, t.f.client, t.treasury, o)
+		if err == nil {
+			tx, err = ethutil.SignTx(ctx, t.f.client, opts, t.f.chainID, faucet, nil)
+		}
+		if err == nil {
+			err = t.f.client.SendTransaction(ctx, tx)
+		}
+		if err != nil {
+			tu.Err = err
+			return tu
+		}
+	} else {
+		method, err := t.tokenMethod(ctx, r.Token, tu.Amount, r.Target)
+		if err != nil {
+			tu.Err = err
+			return tu
+		}
+		tu.Method = method
+		token, err := erc20.NewErc20(r.Token, t.f.client)
+		if err != nil {
+			tu.Err = fmt.Errorf("代币错误：%s", err)
+			return tu
+		}
+		opts, err := ethutil.NewTransactOpts(ctx, t.f.client, t.treasury, o)
+		if err == nil {
+			switch method {
+			case TopUpMint:
+				tx, err = token.AddToken(opts, faucet, tu.Amount)
+			case TopUpSet:
+				tx, err = token.SetToken(opts, faucet, r.Target)
+			default:
+				tx, err = token.Transfer(opts, faucet, tu.Amount)
+			}
+		}
+		if err != nil {
+			tu.Err = err
+			return tu
+		}
+	}
+	tu.Tx = tx.Hash()
+	res, err := ethutil.TrackTx(ctx, t.f.client, tx, ethutil.TrackOptions{Timeout: t.f.timeout})
+	if err != nil {
+		tu.Err = fmt.Errorf("跟踪交易失败：%s", err)
+		return tu
+	}
+	switch res.Status {
+	case ethutil.TxSuccess:
+	case ethutil.TxReverted:
+		reason, err := ethutil.RevertReason(ctx, t.f.client, tx, res.Receipt.BlockNumber, revertABIs...)
+		if err != nil {
+			reason = err.Error()
+		}
+		tu.Err = fmt.Errorf("交易执行失败：%s", reason)
+	default:
+		tu.Err = fmt.Errorf("交易%s", res.Status)
+	}
+	return tu
+}
+
+// tokenMethod 代币允许国库账户铸造时优先铸造，依次尝试 addToken 与 setToken，都不允许时从国库转账
+func (t *TopUpper) tokenMethod(ctx context.Context, token common.Address, amount, target *big.Int) (TopUpMethod, error) {
+	from, faucet := t.treasury.Address(), t.f.Address()
+	if t.f.canCall(ctx, from, token, "addToken", faucet, amount) {
+		return TopUpMint, nil
+	}
+	if t.f.canCall(ctx, from, token, "setToken", faucet, target) {
+		return TopUpSet, nil
+	}
+	erc, err := erc20.NewErc20(token, t.f.client)
+	if err != nil {
+		return "", fmt.Errorf("代币错误：%s", err)
+	}
+	balance, err := erc.BalanceOf(&bind.CallOpts{Context: ctx}, from)
+	if err != nil {
+		return "", fmt.Errorf("获取国库代币余额失败：%s", err)
+	}
+	if balance.Cmp(amount) < 0 {
+		decimals, _ := t.f.Decimals(ctx, token)
+		return "", fmt.Errorf("代币不允许国库账户铸造，国库代币余额 %s 不足 %s", units.Format(balance, decimals), units.Format(amount, decimals))
+	}
+	return TopUpTransfer, nil
+}
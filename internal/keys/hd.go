@@ -0,0 +1,128 @@
+package keys
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultHDBase 以太坊默认的 BIP-44 路径前缀，完整路径为 m/44'/60'/0'/0/n，n 为账户序号
+const DefaultHDBase = "m/44'/60'/0'/0"
+
+// hardened 强化派生的序号起点，路径中写作 n'
+const hardened = 0x80000000
+
+// HDWallet 由 BIP-39 助记词生成的 BIP-32 HD 钱包
+type HDWallet struct {
+	key   []byte
+	chain []byte
+}
+
+// NewHDWallet 校验助记词并生成 HD 钱包，passphrase 为可选的 BIP-39 密码
+func NewHDWallet(mnemonic, passphrase string) (*HDWallet, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if mnemonic == "" {
+		return nil, errors.New("助记词不能为空")
+	}
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		// 不把助记词带进错误信息
+		return nil, errors.New("助记词无效，请检查单词和顺序")
+	}
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	return &HDWallet{key: sum[:32], chain: sum[32:]}, nil
+}
+
+// GenerateMnemonic 生成 12 个单词的新助记词
+func GenerateMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(128)
+	if err != nil {
+		return "", fmt.Errorf("生成助记词失败：%s", err)
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// HDPath 拼出第 index 个账户的路径，base 为空时使用 DefaultHDBase，
+// 也可以写成 m/44'/60'/0'/0/n 的形式
+func HDPath(base string, index uint32) (accounts.DerivationPath, error) {
+	base = strings.TrimSpace(base)
+	if base == "" {
+		base = DefaultHDBase
+	}
+	base = strings.TrimSuffix(base, "/n")
+	path, err := accounts.ParseDerivationPath(base)
+	if err != nil {
+		return nil, fmt.Errorf("派生路径有误：%s", err)
+	}
+	return append(path, index), nil
+}
+
+// Derive 按 BIP-32 派生路径上的私钥
+func (w *HDWallet) Derive(path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	n := crypto.S256().Params().N
+	key := new(big.Int).SetBytes(w.key)
+	chain := w.chain
+	for _, index := range path {
+		var data []byte
+		if index >= hardened {
+			// 强化派生：0x00 || 私钥 || 序号
+			data = append([]byte{0}, common.LeftPadBytes(key.Bytes(), 32)...)
+		} else {
+			pk, err := crypto.ToECDSA(common.LeftPadBytes(key.Bytes(), 32))
+			if err != nil {
+				return nil, err
+			}
+			data = crypto.CompressPubkey(&pk.PublicKey)
+		}
+		var seq [4]byte
+		binary.BigEndian.PutUint32(seq[:], index)
+		mac := hmac.New(sha512.New, chain)
+		mac.Write(data)
+		mac.Write(seq[:])
+		sum := mac.Sum(nil)
+		il := new(big.Int).SetBytes(sum[:32])
+		if il.Cmp(n) >= 0 {
+			return nil, fmt.Errorf("派生 %s 失败：无效的子密钥，请换一个序号", path)
+		}
+		key = il.Add(il, key).Mod(il, n)
+		if key.Sign() == 0 {
+			return nil, fmt.Errorf("派生 %s 失败：无效的子密钥，请换一个序号", path)
+		}
+		chain = sum[32:]
+	}
+	return crypto.ToECDSA(common.LeftPadBytes(key.Bytes(), 32))
+}
+
+// Account 派生 base 路径下第 index 个账户的私钥
+func (w *HDWallet) Account(base string, index uint32) (*ecdsa.PrivateKey, error) {
+	path, err := HDPath(base, index)
+	if err != nil {
+		return nil, err
+	}
+	return w.Derive(path)
+}
+
+// Addresses 列出 base 路径下从 0 开始的 count 个账户地址
+func (w *HDWallet) Addresses(base string, count int) ([]common.Address, error) {
+	addrs := make([]common.Address, 0, count)
+	for i := 0; i < count; i++ {
+		pk, err := w.Account(base, uint32(i))
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, crypto.PubkeyToAddress(pk.PublicKey))
+	}
+	return addrs, nil
+}
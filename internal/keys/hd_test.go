@@ -0,0 +1,74 @@
+package keys
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testMnemonic Hardhat、Anvil 等开发链默认账户的助记词
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestHDWalletAccount(t *testing.T) {
+	tests := []struct {
+		base  string
+		index uint32
+		want  string
+	}{
+		{"", 0, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"},
+		{"", 1, "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"},
+		{"", 2, "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"},
+		{"m/44'/60'/0'/0/n", 1, "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"},
+		{"m/44'/60'/0'/0", 2, "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"},
+	}
+	// 多余的空白不影响助记词
+	w, err := NewHDWallet("  "+strings.ReplaceAll(testMnemonic, " ", "\n ")+" ", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		pk, err := w.Account(tt.base, tt.index)
+		if err != nil {
+			t.Fatalf("派生 %q 第 %d 个账户失败：%s", tt.base, tt.index, err)
+		}
+		if got := crypto.PubkeyToAddress(pk.PublicKey).Hex(); got != tt.want {
+			t.Errorf("%q 第 %d 个账户为 %s，预期 %s", tt.base, tt.index, got, tt.want)
+		}
+	}
+
+	addrs, err := w.Addresses("", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, addr := range addrs {
+		if addr.Hex() != tests[i].want {
+			t.Errorf("Addresses 第 %d 个为 %s，预期 %s", i, addr.Hex(), tests[i].want)
+		}
+	}
+
+	// BIP-39 密码不同时派生出不同的账户
+	other, err := NewHDWallet(testMnemonic, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pk, _ := other.Account("", 0); crypto.PubkeyToAddress(pk.PublicKey).Hex() == tests[0].want {
+		t.Error("BIP-39 密码没有生效")
+	}
+}
+
+func TestHDWalletInvalid(t *testing.T) {
+	for _, mnemonic := range []string{"", "test test test test test test test test test test test test", "hello world"} {
+		_, err := NewHDWallet(mnemonic, "")
+		if err == nil {
+			t.Errorf("助记词 %q 应当无效", mnemonic)
+			continue
+		}
+		if mnemonic != "" && strings.Contains(err.Error(), mnemonic) {
+			t.Errorf("错误信息 %q 中包含了助记词", err)
+		}
+	}
+	if _, err := HDPath("m/44'/x", 0); err == nil {
+		t.Error("派生路径有误时应当报错")
+	}
+}
@@ -0,0 +1,200 @@
+// Package keys 签名密钥的来源：keystore 文件、密钥保管库、助记词、环境变量、私钥文件与远程签名服务，按 Spec 打开成 ethutil.Signer
+package keys
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/naiba/eth-tools/internal/ethutil"
+)
+
+// Kind 密钥来源的类型
+type Kind string
+
+const (
+	KindKeystore Kind = "keystore" // 加密的 keystore JSON 文件，需要密码
+	KindEnv      Kind = "env"      // 环境变量中的十六进制私钥
+	KindFile     Kind = "file"     // 文件中的十六进制私钥
+	KindVault    Kind = "vault"    // 本地密钥保管库中的一个密钥，需要密码
+	KindRemote   Kind = "remote"   // 远程签名服务，私钥不进入本进程
+	KindMnemonic Kind = "mnemonic" // BIP-39 助记词派生的 HD 钱包账户
+)
+
+// Kinds 界面上可选的密钥来源，与 KindNames 一一对应
+var Kinds = []Kind{KindKeystore, KindVault, KindMnemonic, KindEnv, KindFile, KindRemote}
+
+// KindNames 密钥来源在界面上显示的名称
+var KindNames = []string{"Keystore 文件", "密钥保管库", "助记词", "环境变量", "私钥文件", "远程签名"}
+
+// ParseKind 解析密钥来源类型
+func ParseKind(s string) (Kind, error) {
+	kind := Kind(strings.ToLower(strings.TrimSpace(s)))
+	for _, k := range Kinds {
+		if k == kind {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("未知的密钥来源：%s", s)
+}
+
+// Spec 密钥来源的描述，只记录去哪里找密钥，不包含密钥本身
+type Spec struct {
+	Kind     Kind   `json:"kind"`
+	Location string `json:"location"`          // keystore 或私钥文件路径、环境变量名、保管库文件路径（留空为默认位置）、签名服务地址、助记词的派生路径（留空为 m/44'/60'/0'/0/n）
+	Account  string `json:"account,omitempty"` // 保管库中的密钥名称，远程签名服务中的账户地址（留空使用第一个），或助记词派生的账户序号（留空为 0）
+}
+
+// Secret 打开密钥来源时输入的秘密信息，只在内存中使用，不会保存
+type Secret struct {
+	Passphrase string // keystore、保管库的密码，或助记词的 BIP-39 密码（可以为空）
+	Mnemonic   string // BIP-39 助记词
+}
+
+// NeedPassphrase 打开该来源是否必须输入密码
+func (s Spec) NeedPassphrase() bool {
+	return s.Kind == KindKeystore || s.Kind == KindVault
+}
+
+// Index 助记词派生的账户序号
+func (s Spec) Index() (uint32, error) {
+	if strings.TrimSpace(s.Account) == "" {
+		return 0, nil
+	}
+	index, err := strconv.ParseUint(strings.TrimSpace(s.Account), 10, 31)
+	if err != nil {
+		return 0, fmt.Errorf("账户序号有误：%s", s.Account)
+	}
+	return uint32(index), nil
+}
+
+func (s Spec) String() string {
+	switch s.Kind {
+	case KindEnv:
+		return "环境变量 " + s.Location
+	case KindVault:
+		return "保管库密钥 " + s.Account
+	case KindRemote:
+		return "远程签名 " + s.Location
+	case KindMnemonic:
+		base := s.Location
+		if base == "" {
+			base = DefaultHDBase
+		}
+		index, _ := s.Index()
+		return fmt.Sprintf("助记词 %s/%d", strings.TrimSuffix(base, "/n"), index)
+	}
+	return fmt.Sprintf("%s %s", s.Kind, s.Location)
+}
+
+// Open 打开密钥来源
+func Open(spec Spec, secret Secret) (ethutil.Signer, error) {
+	if spec.Kind == KindRemote {
+		s, err := OpenRemote(spec.Location, spec.Account)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	pk, err := PrivateKey(spec, secret)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySigner(pk), nil
+}
+
+// PrivateKey 读取并解密本地的私钥，远程签名服务不提供私钥
+func PrivateKey(spec Spec, secret Secret) (*ecdsa.PrivateKey, error) {
+	passphrase := secret.Passphrase
+	switch spec.Kind {
+	case KindKeystore:
+		data, err := ioutil.ReadFile(spec.Location)
+		if err != nil {
+			return nil, fmt.Errorf("读取 keystore 文件失败：%s", err)
+		}
+		key, err := decryptKeystore(data, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		return key.PrivateKey, nil
+	case KindEnv:
+		if spec.Location == "" {
+			return nil, errors.New("未指定保存私钥的环境变量")
+		}
+		hex := strings.TrimSpace(os.Getenv(spec.Location))
+		if hex == "" {
+			return nil, fmt.Errorf("环境变量 %s 中没有私钥", spec.Location)
+		}
+		return ParseHexKey(hex)
+	case KindFile:
+		data, err := ioutil.ReadFile(spec.Location)
+		if err != nil {
+			return nil, fmt.Errorf("读取私钥文件失败：%s", err)
+		}
+		return ParseHexKey(strings.TrimSpace(string(data)))
+	case KindVault:
+		v, err := OpenVault(spec.Location)
+		if err != nil {
+			return nil, err
+		}
+		return v.Unlock(spec.Account, passphrase)
+	case KindMnemonic:
+		index, err := spec.Index()
+		if err != nil {
+			return nil, err
+		}
+		w, err := NewHDWallet(secret.Mnemonic, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		return w.Account(spec.Location, index)
+	case KindRemote:
+		return nil, errors.New("远程签名服务不提供私钥")
+	}
+	return nil, fmt.Errorf("未知的密钥来源：%s", spec.Kind)
+}
+
+// ParseHexKey 解析十六进制私钥，可以带 0x 前缀
+func ParseHexKey(hex string) (*ecdsa.PrivateKey, error) {
+	pk, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(hex), "0x"))
+	if err != nil {
+		// 不把原文带进错误信息，避免私钥出现在日志里
+		return nil, errors.New("解析私钥错误：不是有效的十六进制私钥")
+	}
+	return pk, nil
+}
+
+// KeySigner 用本进程中的私钥签名
+type KeySigner struct {
+	key  *ecdsa.PrivateKey
+	addr common.Address
+}
+
+// NewKeySigner 包装一个已解锁的私钥
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{key: key, addr: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+// Address 私钥对应的地址
+func (s *KeySigner) Address() common.Address {
+	return s.addr
+}
+
+// SignTx 使用 bind.NewKeyedTransactorWithChainID 生成的签名函数签名交易
+func (s *KeySigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	opts, err := bind.NewKeyedTransactorWithChainID(s.key, chainID)
+	if err != nil {
+		return nil, err
+	}
+	return opts.Signer(s.addr, tx)
+}
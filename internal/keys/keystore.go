@@ -0,0 +1,146 @@
+package keys
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+// DefaultKeystoreDir 导入的 keystore 文件存放的目录：用户配置目录下的 eth-tools/keystore
+func DefaultKeystoreDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "keystore"
+	}
+	return filepath.Join(dir, "eth-tools", "keystore")
+}
+
+// KeystoreAddress 读取 keystore V3 文件中记录的地址，不需要密码，用于在输入密码前确认文件
+func KeystoreAddress(path string) (common.Address, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("读取 keystore 文件失败：%s", err)
+	}
+	return keystoreAddress(data)
+}
+
+func keystoreAddress(data []byte) (common.Address, error) {
+	var head struct {
+		Address string          `json:"address"`
+		Version int             `json:"version"`
+		Crypto  json.RawMessage `json:"crypto"`
+		Crypto2 json.RawMessage `json:"Crypto"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return common.Address{}, fmt.Errorf("不是 keystore 文件：%s", err)
+	}
+	if head.Version != 3 {
+		return common.Address{}, fmt.Errorf("只支持 V3 格式的 keystore，文件版本为 %d", head.Version)
+	}
+	if len(head.Crypto) == 0 && len(head.Crypto2) == 0 {
+		return common.Address{}, errors.New("keystore 文件中没有加密数据")
+	}
+	if !common.IsHexAddress(head.Address) {
+		return common.Address{}, errors.New("keystore 文件中没有地址")
+	}
+	return common.HexToAddress(head.Address), nil
+}
+
+// EncryptKeystore 用密码把私钥加密成 keystore V3 格式
+func EncryptKeystore(pk *ecdsa.PrivateKey, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("密码不能为空")
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, fmt.Errorf("生成密钥 ID 失败：%s", err)
+	}
+	key := &keystore.Key{Id: id, Address: crypto.PubkeyToAddress(pk.PublicKey), PrivateKey: pk}
+	data, err := keystore.EncryptKey(key, passphrase, keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return nil, fmt.Errorf("加密私钥失败：%s", err)
+	}
+	return data, nil
+}
+
+// KeystoreFileName geth 使用的 keystore 文件名：UTC--时间--地址
+func KeystoreFileName(addr common.Address) string {
+	ts := time.Now().UTC().Format("2006-01-02T15-04-05.000000000Z")
+	return fmt.Sprintf("UTC--%s--%s", ts, strings.ToLower(addr.Hex()[2:]))
+}
+
+// ExportKeystore 把私钥加密写入 keystore 文件，path 为目录时使用标准文件名，
+// 不覆盖已有文件，返回写入的路径
+func ExportKeystore(pk *ecdsa.PrivateKey, passphrase, path string) (string, error) {
+	data, err := EncryptKeystore(pk, passphrase)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, KeystoreFileName(crypto.PubkeyToAddress(pk.PublicKey)))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", fmt.Errorf("写入 keystore 文件失败：%s", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", fmt.Errorf("写入 keystore 文件失败：%s", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return "", fmt.Errorf("写入 keystore 文件失败：%s", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("写入 keystore 文件失败：%s", err)
+	}
+	return path, nil
+}
+
+// decryptKeystore 解密 keystore，并核对文件中记录的地址与解密出的私钥一致
+func decryptKeystore(data []byte, passphrase string) (*keystore.Key, error) {
+	addr, err := keystoreAddress(data)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("解密 keystore 失败：%s", err)
+	}
+	if key.Address != addr {
+		return nil, errors.New("keystore 文件中的地址与私钥不符")
+	}
+	return key, nil
+}
+
+// ImportKeystore 用密码验证 keystore 文件后复制到 DefaultKeystoreDir，返回新文件的路径
+func ImportKeystore(src, passphrase string) (string, common.Address, error) {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return "", common.Address{}, fmt.Errorf("读取 keystore 文件失败：%s", err)
+	}
+	key, err := decryptKeystore(data, passphrase)
+	if err != nil {
+		return "", common.Address{}, err
+	}
+	addr := key.Address
+	dir := DefaultKeystoreDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", addr, fmt.Errorf("导入 keystore 失败：%s", err)
+	}
+	dst := filepath.Join(dir, KeystoreFileName(addr))
+	if err := ioutil.WriteFile(dst, data, 0600); err != nil {
+		return "", addr, fmt.Errorf("导入 keystore 失败：%s", err)
+	}
+	return dst, addr, nil
+}
@@ -0,0 +1,63 @@
+package keys
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+// writeKeystore 用轻量的 scrypt 参数生成 keystore 文件，address 非空时改写文件中的地址
+func writeKeystore(t *testing.T, address string) (string, *keystore.Key) {
+	t.Helper()
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &keystore.Key{Id: uuid.New(), Address: crypto.PubkeyToAddress(pk.PublicKey), PrivateKey: pk}
+	data, err := keystore.EncryptKey(key, "pw", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address != "" {
+		var m map[string]interface{}
+		if err := json.Unmarshal(data, &m); err != nil {
+			t.Fatal(err)
+		}
+		m["address"] = address
+		if data, err = json.Marshal(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path, key
+}
+
+func TestPrivateKeyKeystore(t *testing.T) {
+	path, key := writeKeystore(t, "")
+	pk, err := PrivateKey(Spec{Kind: KindKeystore, Location: path}, Secret{Passphrase: "pw"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(pk.PublicKey) != key.Address {
+		t.Fatalf("解密出的地址 %s，预期 %s", crypto.PubkeyToAddress(pk.PublicKey).Hex(), key.Address.Hex())
+	}
+	if _, err := PrivateKey(Spec{Kind: KindKeystore, Location: path}, Secret{Passphrase: "bad"}); err == nil {
+		t.Fatal("密码错误时应当失败")
+	}
+}
+
+func TestPrivateKeyKeystoreAddressMismatch(t *testing.T) {
+	path, _ := writeKeystore(t, "0000000000000000000000000000000000000001")
+	_, err := PrivateKey(Spec{Kind: KindKeystore, Location: path}, Secret{Passphrase: "pw"})
+	if err == nil || err.Error() != "keystore 文件中的地址与私钥不符" {
+		t.Fatalf("地址不符时应当失败，实际：%v", err)
+	}
+}
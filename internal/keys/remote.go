@@ -0,0 +1,213 @@
+package keys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// 远程签名服务的两种接口
+const (
+	protoClef = "clef" // account_list / account_signTransaction
+	protoEth  = "eth"  // eth_accounts / eth_signTransaction，如 Web3Signer
+)
+
+// methodNotFound JSON-RPC 方法不存在的错误码
+const methodNotFound = -32601
+
+// remoteTimeout 连接签名服务、列出账户的超时时间
+const remoteTimeout = time.Second * 30
+
+// RemoteSigner 通过 JSON-RPC 请求外部签名服务签名，私钥不进入本进程。
+// 支持 Clef 的 account_signTransaction 与 Web3Signer 的 eth_signTransaction，
+// 连接时自动识别，地址可以是 http(s)、ws(s) 或 IPC 文件路径。
+type RemoteSigner struct {
+	rc    *rpc.Client
+	proto string
+	addr  common.Address
+}
+
+// OpenRemote 连接远程签名服务，account 为空时使用服务中的第一个账户
+func OpenRemote(endpoint, account string) (*RemoteSigner, error) {
+	if endpoint == "" {
+		return nil, errors.New("未指定远程签名服务地址")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), remoteTimeout)
+	defer cancel()
+	rc, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("连接远程签名服务失败：%s", err)
+	}
+	s := &RemoteSigner{rc: rc, proto: protoClef}
+	var accs []common.Address
+	err = rc.CallContext(ctx, &accs, "account_list")
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == methodNotFound {
+		s.proto = protoEth
+		err = rc.CallContext(ctx, &accs, "eth_accounts")
+	}
+	if err != nil {
+		rc.Close()
+		return nil, fmt.Errorf("获取远程签名服务的账户失败：%s", err)
+	}
+	if len(accs) == 0 {
+		rc.Close()
+		return nil, errors.New("远程签名服务没有可用的账户")
+	}
+	s.addr = accs[0]
+	if account != "" {
+		if !common.IsHexAddress(account) {
+			rc.Close()
+			return nil, fmt.Errorf("账户地址有误：%s", account)
+		}
+		s.addr = common.HexToAddress(account)
+		if !containsAddress(accs, s.addr) {
+			rc.Close()
+			return nil, fmt.Errorf("远程签名服务中没有账户 %s", account)
+		}
+	}
+	return s, nil
+}
+
+func containsAddress(accs []common.Address, addr common.Address) bool {
+	for _, a := range accs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// Address 签名账户的地址
+func (s *RemoteSigner) Address() common.Address {
+	return s.addr
+}
+
+// Close 断开与签名服务的连接
+func (s *RemoteSigner) Close() {
+	s.rc.Close()
+}
+
+// SignTx 把交易发给签名服务签名，等待时间由 ctx 控制（Clef 需要人工确认）。
+// 返回前核对签名者与交易内容，签名服务改动了交易或用错了账户、链 ID 时拒绝使用。
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := newTxArgs(s.addr, tx, chainID)
+	var raw hexutil.Bytes
+	switch s.proto {
+	case protoClef:
+		var res struct {
+			Raw hexutil.Bytes `json:"raw"`
+		}
+		if err := s.rc.CallContext(ctx, &res, "account_signTransaction", args); err != nil {
+			return nil, fmt.Errorf("远程签名失败：%s", err)
+		}
+		raw = res.Raw
+	default:
+		if err := s.rc.CallContext(ctx, &raw, "eth_signTransaction", args); err != nil {
+			return nil, fmt.Errorf("远程签名失败：%s", err)
+		}
+	}
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("解析远程签名结果失败：%s", err)
+	}
+	if err := checkSigned(tx, signed, s.addr, chainID); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// checkSigned 核对签名后的交易与待签名的交易一致，且由 from 在 chainID 上签名
+func checkSigned(tx, signed *types.Transaction, from common.Address, chainID *big.Int) error {
+	if signed.Protected() && signed.ChainId().Cmp(chainID) != 0 {
+		return fmt.Errorf("签名结果的链 ID 为 %s，与预期的 %s 不符，已拒绝", signed.ChainId(), chainID)
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	sender, err := types.Sender(signer, signed)
+	if err != nil {
+		return fmt.Errorf("无法验证签名结果：%s", err)
+	}
+	if sender != from {
+		return fmt.Errorf("签名结果的签名者为 %s，不是 %s，已拒绝", sender.Hex(), from.Hex())
+	}
+	if signed.Type() != tx.Type() || signer.Hash(signed) != signer.Hash(tx) {
+		return errors.New("签名服务返回的交易内容与请求不符，已拒绝")
+	}
+	return nil
+}
+
+// txArgs 签名请求中的交易参数，Clef 与 Web3Signer 使用相同的字段
+type txArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId,omitempty"`
+}
+
+func newTxArgs(from common.Address, tx *types.Transaction, chainID *big.Int) txArgs {
+	args := txArgs{
+		From:    from,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+	return args
+}
+
+// toTransaction 按签名请求的参数还原待签名的交易
+func (args *txArgs) toTransaction() *types.Transaction {
+	value := new(big.Int)
+	if args.Value != nil {
+		value = args.Value.ToInt()
+	}
+	if args.MaxFeePerGas != nil {
+		tip := new(big.Int)
+		if args.MaxPriorityFeePerGas != nil {
+			tip = args.MaxPriorityFeePerGas.ToInt()
+		}
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   args.ChainID.ToInt(),
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: tip,
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     value,
+			Data:      args.Data,
+		})
+	}
+	price := new(big.Int)
+	if args.GasPrice != nil {
+		price = args.GasPrice.ToInt()
+	}
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    uint64(args.Nonce),
+		GasPrice: price,
+		Gas:      uint64(args.Gas),
+		To:       args.To,
+		Value:    value,
+		Data:     args.Data,
+	})
+}
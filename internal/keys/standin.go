@@ -0,0 +1,177 @@
+package keys
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// SignRules 替身签名服务的白名单规则，空的规则项表示不限制
+type SignRules struct {
+	ChainIDs    []uint64         // 允许的链 ID
+	To          []common.Address // 允许的接收地址（合约地址或钱包地址）
+	Methods     [][4]byte        // 允许调用的合约方法选择器，限制后不允许不带数据的转账
+	MaxValue    *big.Int         // 单笔交易最多转出的原生币（wei）
+	AllowCreate bool             // 是否允许部署合约
+}
+
+// Check 检查交易是否符合规则，不符合时返回原因
+func (r *SignRules) Check(tx *types.Transaction, chainID *big.Int) error {
+	if len(r.ChainIDs) > 0 {
+		allowed := false
+		for _, id := range r.ChainIDs {
+			if chainID.IsUint64() && chainID.Uint64() == id {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("不允许在链 %s 上签名", chainID)
+		}
+	}
+	if tx.To() == nil {
+		if !r.AllowCreate {
+			return errors.New("不允许部署合约")
+		}
+	} else if len(r.To) > 0 && !containsAddress(r.To, *tx.To()) {
+		return fmt.Errorf("接收地址 %s 不在白名单中", tx.To().Hex())
+	}
+	if len(r.Methods) > 0 && tx.To() != nil {
+		if len(tx.Data()) < 4 {
+			return errors.New("只允许调用白名单中的合约方法")
+		}
+		allowed := false
+		for _, m := range r.Methods {
+			if string(m[:]) == string(tx.Data()[:4]) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("合约方法 %s 不在白名单中", hexutil.Encode(tx.Data()[:4]))
+		}
+	}
+	if r.MaxValue != nil && tx.Value().Cmp(r.MaxValue) > 0 {
+		return fmt.Errorf("转出 %s wei 超过单笔上限 %s wei", tx.Value(), r.MaxValue)
+	}
+	return nil
+}
+
+// ParseMethod 把合约方法签名（如 transfer(address,uint256)）或 4 字节选择器（如 0xa9059cbb）转为选择器
+func ParseMethod(s string) ([4]byte, error) {
+	var sel [4]byte
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "(") {
+		copy(sel[:], crypto.Keccak256([]byte(strings.ReplaceAll(s, " ", "")))[:4])
+		return sel, nil
+	}
+	b, err := hexutil.Decode(s)
+	if err != nil || len(b) != 4 {
+		return sel, fmt.Errorf("合约方法有误：%s", s)
+	}
+	copy(sel[:], b)
+	return sel, nil
+}
+
+// StandInSigner 本地的替身签名服务，用于测试与演练：
+// 和 Clef、Web3Signer 一样通过 JSON-RPC 提供签名，只签符合 SignRules 的交易
+type StandInSigner struct {
+	key   *ecdsa.PrivateKey
+	addr  common.Address
+	rules SignRules
+
+	// OnSign 每次收到签名请求后调用，err 为空表示已签名
+	OnSign func(tx *types.Transaction, err error)
+}
+
+// NewStandInSigner 用私钥和白名单规则创建替身签名服务
+func NewStandInSigner(key *ecdsa.PrivateKey, rules SignRules) *StandInSigner {
+	return &StandInSigner{key: key, addr: crypto.PubkeyToAddress(key.PublicKey), rules: rules}
+}
+
+// Address 签名账户的地址
+func (s *StandInSigner) Address() common.Address {
+	return s.addr
+}
+
+// Server 提供 Clef 的 account_ 接口与 Web3Signer 的 eth_ 接口的 RPC 服务，
+// 可以用 http.Serve 或 ServeListener（IPC）对外提供
+func (s *StandInSigner) Server() (*rpc.Server, error) {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("account", &clefAPI{s}); err != nil {
+		return nil, err
+	}
+	if err := srv.RegisterName("eth", &ethSignAPI{s}); err != nil {
+		return nil, err
+	}
+	return srv, nil
+}
+
+func (s *StandInSigner) sign(args txArgs) (*types.Transaction, error) {
+	if args.From != s.addr {
+		return nil, fmt.Errorf("没有账户 %s", args.From.Hex())
+	}
+	if args.ChainID == nil {
+		return nil, errors.New("签名请求中没有链 ID")
+	}
+	chainID := args.ChainID.ToInt()
+	tx := args.toTransaction()
+	err := s.rules.Check(tx, chainID)
+	if err == nil {
+		tx, err = types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+	}
+	if s.OnSign != nil {
+		s.OnSign(tx, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("拒绝签名：%s", err)
+	}
+	return tx, nil
+}
+
+// clefAPI Clef 兼容的接口：account_list、account_signTransaction
+type clefAPI struct {
+	s *StandInSigner
+}
+
+func (api *clefAPI) List() []common.Address {
+	return []common.Address{api.s.addr}
+}
+
+func (api *clefAPI) SignTransaction(ctx context.Context, args txArgs) (map[string]interface{}, error) {
+	tx, err := api.s.sign(args)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"raw": hexutil.Bytes(raw), "tx": tx}, nil
+}
+
+// ethSignAPI Web3Signer 兼容的接口：eth_accounts、eth_signTransaction
+type ethSignAPI struct {
+	s *StandInSigner
+}
+
+func (api *ethSignAPI) Accounts() []common.Address {
+	return []common.Address{api.s.addr}
+}
+
+func (api *ethSignAPI) SignTransaction(ctx context.Context, args txArgs) (hexutil.Bytes, error) {
+	tx, err := api.s.sign(args)
+	if err != nil {
+		return nil, err
+	}
+	return tx.MarshalBinary()
+}
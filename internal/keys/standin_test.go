@@ -0,0 +1,217 @@
+package keys
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	testToken    = common.HexToAddress("0x000000000000000000000000000000000000700c")
+	testOther    = common.HexToAddress("0x0000000000000000000000000000000000000bad")
+	testChainID  = big.NewInt(1337)
+	transferData = append(hexutil.MustDecode("0xa9059cbb"), make([]byte, 64)...)
+	approveData  = append(hexutil.MustDecode("0x095ea7b3"), make([]byte, 64)...)
+)
+
+func testRules() SignRules {
+	transfer, _ := ParseMethod("transfer(address, uint256)")
+	return SignRules{
+		ChainIDs: []uint64{1337},
+		To:       []common.Address{testToken},
+		Methods:  [][4]byte{transfer},
+		MaxValue: big.NewInt(params.GWei),
+	}
+}
+
+func dynamicTx(to *common.Address, value int64, data []byte) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   testChainID,
+		Nonce:     7,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: big.NewInt(30 * params.GWei),
+		Gas:       60000,
+		To:        to,
+		Value:     big.NewInt(value),
+		Data:      data,
+	})
+}
+
+// serve 通过 HTTP 提供 RPC 服务，测试结束时关闭
+func serve(t *testing.T, srv *rpc.Server) string {
+	t.Helper()
+	hs := httptest.NewServer(srv)
+	t.Cleanup(func() {
+		hs.Close()
+		srv.Stop()
+	})
+	return hs.URL
+}
+
+func newStandIn(t *testing.T) *StandInSigner {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewStandInSigner(key, testRules())
+}
+
+// checkRemoteSign 通过远程签名服务签名符合规则的 EIP-1559 与 Legacy 交易，并检查每条规则都会拒绝签名
+func checkRemoteSign(t *testing.T, remote *RemoteSigner, from common.Address) {
+	t.Helper()
+	ctx := context.Background()
+	legacy := types.NewTx(&types.LegacyTx{Nonce: 3, GasPrice: big.NewInt(params.GWei), Gas: 60000, To: &testToken, Value: big.NewInt(0), Data: transferData})
+	for _, tx := range []*types.Transaction{dynamicTx(&testToken, 0, transferData), legacy} {
+		signed, err := remote.SignTx(ctx, tx, testChainID)
+		if err != nil {
+			t.Fatalf("%s：签名失败：%s", remote.proto, err)
+		}
+		sender, err := types.Sender(types.LatestSignerForChainID(testChainID), signed)
+		if err != nil || sender != from {
+			t.Fatalf("%s：签名者 %s, %v，预期 %s", remote.proto, sender.Hex(), err, from.Hex())
+		}
+		if signed.Type() != tx.Type() || signed.Nonce() != tx.Nonce() || string(signed.Data()) != string(tx.Data()) {
+			t.Fatalf("%s：签名后的交易与请求不符", remote.proto)
+		}
+	}
+
+	for _, c := range []struct {
+		name    string
+		tx      *types.Transaction
+		chainID *big.Int
+		reason  string
+	}{
+		{"链 ID", dynamicTx(&testToken, 0, transferData), big.NewInt(1), "不允许在链 1 上签名"},
+		{"接收地址", dynamicTx(&testOther, 0, transferData), testChainID, "接收地址 " + testOther.Hex() + " 不在白名单中"},
+		{"合约方法", dynamicTx(&testToken, 0, approveData), testChainID, "合约方法 0x095ea7b3 不在白名单中"},
+		{"不带数据的转账", dynamicTx(&testToken, 0, nil), testChainID, "只允许调用白名单中的合约方法"},
+		{"转出数量", dynamicTx(&testToken, params.GWei+1, transferData), testChainID, "超过单笔上限"},
+		{"部署合约", dynamicTx(nil, 0, []byte{0x60, 0x00}), testChainID, "不允许部署合约"},
+	} {
+		_, err := remote.SignTx(ctx, c.tx, c.chainID)
+		if err == nil || !strings.Contains(err.Error(), "拒绝签名") || !strings.Contains(err.Error(), c.reason) {
+			t.Errorf("%s：%s 不符合规则时错误为 %v，预期包含 %q", remote.proto, c.name, err, c.reason)
+		}
+	}
+}
+
+func TestStandInSignerClef(t *testing.T) {
+	s := newStandIn(t)
+	var (
+		mu               sync.Mutex
+		signed, rejected int
+	)
+	s.OnSign = func(tx *types.Transaction, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			rejected++
+		} else {
+			signed++
+		}
+	}
+	srv, err := s.Server()
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := serve(t, srv)
+
+	remote, err := OpenRemote(url, "")
+	if err != nil {
+		t.Fatalf("连接替身签名服务失败：%s", err)
+	}
+	defer remote.Close()
+	if remote.proto != protoClef || remote.Address() != s.Address() {
+		t.Fatalf("接口 %s、账户 %s，预期 clef 与 %s", remote.proto, remote.Address().Hex(), s.Address().Hex())
+	}
+	checkRemoteSign(t, remote, s.Address())
+	mu.Lock()
+	defer mu.Unlock()
+	if signed != 2 || rejected != 6 {
+		t.Errorf("OnSign 记录签名 %d 次、拒绝 %d 次，预期 2 次与 6 次", signed, rejected)
+	}
+
+	if _, err := OpenRemote(url, testOther.Hex()); err == nil {
+		t.Error("签名服务中没有的账户应当返回错误")
+	}
+}
+
+func TestStandInSignerEth(t *testing.T) {
+	s := newStandIn(t)
+	// 只提供 eth_ 接口的服务，如 Web3Signer
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("eth", &ethSignAPI{s}); err != nil {
+		t.Fatal(err)
+	}
+	remote, err := OpenRemote(serve(t, srv), s.Address().Hex())
+	if err != nil {
+		t.Fatalf("连接签名服务失败：%s", err)
+	}
+	defer remote.Close()
+	if remote.proto != protoEth {
+		t.Fatalf("接口 %s，预期 eth", remote.proto)
+	}
+	checkRemoteSign(t, remote, s.Address())
+}
+
+// tamperAPI 改动交易或用错账户签名的签名服务
+type tamperAPI struct {
+	from common.Address
+	key  *ecdsa.PrivateKey
+	edit func(tx *types.Transaction) *types.Transaction
+}
+
+func (api *tamperAPI) Accounts() []common.Address {
+	return []common.Address{api.from}
+}
+
+func (api *tamperAPI) SignTransaction(args txArgs) (hexutil.Bytes, error) {
+	tx := api.edit(args.toTransaction())
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(args.ChainID.ToInt()), api.key)
+	if err != nil {
+		return nil, err
+	}
+	return signed.MarshalBinary()
+}
+
+func TestRemoteSignerRejectsTampering(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	for _, c := range []struct {
+		name   string
+		key    *ecdsa.PrivateKey
+		edit   func(tx *types.Transaction) *types.Transaction
+		reason string
+	}{
+		{"改动接收地址", key, func(*types.Transaction) *types.Transaction { return dynamicTx(&testOther, 0, transferData) }, "交易内容与请求不符"},
+		{"改动数量", key, func(*types.Transaction) *types.Transaction { return dynamicTx(&testToken, 1, transferData) }, "交易内容与请求不符"},
+		{"用错账户", other, func(tx *types.Transaction) *types.Transaction { return tx }, "签名者"},
+	} {
+		srv := rpc.NewServer()
+		if err := srv.RegisterName("eth", &tamperAPI{from: from, key: c.key, edit: c.edit}); err != nil {
+			t.Fatal(err)
+		}
+		remote, err := OpenRemote(serve(t, srv), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = remote.SignTx(context.Background(), dynamicTx(&testToken, 0, transferData), testChainID)
+		if err == nil || !strings.Contains(err.Error(), c.reason) {
+			t.Errorf("%s：错误为 %v，预期包含 %q", c.name, err, c.reason)
+		}
+		remote.Close()
+	}
+}
@@ -0,0 +1,138 @@
+package keys
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Vault 本地密钥保管库：一个文件里按名称保存多个用密码加密的私钥，
+// 每个私钥都使用 keystore V3 格式加密，文件中没有明文
+type Vault struct {
+	Keys map[string]json.RawMessage `json:"keys"`
+
+	path string
+}
+
+// DefaultVaultPath 默认的保管库位置：用户配置目录下的 eth-tools/vault.json
+func DefaultVaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "vault.json"
+	}
+	return filepath.Join(dir, "eth-tools", "vault.json")
+}
+
+// OpenVault 打开保管库，path 为空时使用默认位置，文件不存在时返回空的保管库
+func OpenVault(path string) (*Vault, error) {
+	if path == "" {
+		path = DefaultVaultPath()
+	}
+	v := &Vault{Keys: make(map[string]json.RawMessage), path: path}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return v, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥保管库失败：%s", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("解析密钥保管库失败：%s", err)
+	}
+	if v.Keys == nil {
+		v.Keys = make(map[string]json.RawMessage)
+	}
+	return v, nil
+}
+
+// Names 保管库中的密钥名称，按名称排序
+func (v *Vault) Names() []string {
+	names := make([]string, 0, len(v.Keys))
+	for name := range v.Keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Address 密钥对应的地址，不需要密码
+func (v *Vault) Address(name string) (common.Address, error) {
+	data, has := v.Keys[name]
+	if !has {
+		return common.Address{}, fmt.Errorf("保管库中没有名为 %s 的密钥", name)
+	}
+	var head struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return common.Address{}, fmt.Errorf("解析密钥 %s 失败：%s", name, err)
+	}
+	return common.HexToAddress(head.Address), nil
+}
+
+// Add 用密码加密私钥后加入保管库，已有同名密钥时报错，需要调用 Save 才会写入文件
+func (v *Vault) Add(name string, pk *ecdsa.PrivateKey, passphrase string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("密钥名称不能为空")
+	}
+	if _, has := v.Keys[name]; has {
+		return fmt.Errorf("保管库中已有名为 %s 的密钥", name)
+	}
+	data, err := EncryptKeystore(pk, passphrase)
+	if err != nil {
+		return err
+	}
+	v.Keys[name] = data
+	return nil
+}
+
+// Remove 从保管库删除密钥，需要调用 Save 才会写入文件
+func (v *Vault) Remove(name string) error {
+	if _, has := v.Keys[name]; !has {
+		return fmt.Errorf("保管库中没有名为 %s 的密钥", name)
+	}
+	delete(v.Keys, name)
+	return nil
+}
+
+// Unlock 用密码解密保管库中的私钥
+func (v *Vault) Unlock(name, passphrase string) (*ecdsa.PrivateKey, error) {
+	data, has := v.Keys[name]
+	if !has {
+		return nil, fmt.Errorf("保管库中没有名为 %s 的密钥", name)
+	}
+	key, err := keystore.DecryptKey(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("解锁密钥 %s 失败：%s", name, err)
+	}
+	return key.PrivateKey, nil
+}
+
+// Save 写回保管库文件，只有当前用户可读
+func (v *Vault) Save() error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("保存密钥保管库失败：%s", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(v.path), 0700); err != nil {
+		return fmt.Errorf("保存密钥保管库失败：%s", err)
+	}
+	tmp := v.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("保存密钥保管库失败：%s", err)
+	}
+	if err := os.Rename(tmp, v.path); err != nil {
+		return fmt.Errorf("保存密钥保管库失败：%s", err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package merkle
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Deploy 部署与树根对应的领取合约，部署后还需要向合约转入 tree.Total() 个代币。
+// 部署账户是合约的 owner，可以用 sweep 收回无人领取的代币
+func Deploy(auth *bind.TransactOpts, backend bind.ContractBackend, token common.Address, tree *Tree) (common.Address, *types.Transaction, *MerkleDistributor, error) {
+	return DeployMerkleDistributor(auth, backend, token, tree.Root(), auth.From)
+}
+
+// ProofArgs 把证明转换成合约调用参数
+func ProofArgs(proof []common.Hash) [][32]byte {
+	args := make([][32]byte, len(proof))
+	for i, p := range proof {
+		args[i] = p
+	}
+	return args
+}
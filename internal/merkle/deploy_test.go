@@ -0,0 +1,198 @@
+package merkle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/naiba/eth-tools/internal/testchain"
+)
+
+// distributorEnv 模拟链上的领取合约：账户 0 部署代币与合约并转入领取总量，账户 1 是普通用户
+type distributorEnv struct {
+	chain    *testchain.Chain
+	token    *testchain.TestToken
+	tree     *Tree
+	contract *MerkleDistributor
+	addr     common.Address
+	accounts []common.Address
+}
+
+func newDistributorEnv(t *testing.T, quiet bool) *distributorEnv {
+	t.Helper()
+	c := testchain.New(t, 2)
+	tokenAddr, token := c.DeployToken(t, 0, quiet)
+	c.Mint(t, token, c.Address(0), 10000)
+	e := &distributorEnv{chain: c, token: token}
+	amounts := make(map[common.Address]*big.Int)
+	for i := 0; i < 5; i++ {
+		account := common.BigToAddress(big.NewInt(int64(0x100 + i)))
+		e.accounts = append(e.accounts, account)
+		amounts[account] = big.NewInt(int64(100 * (i + 1)))
+	}
+	tree, err := NewTree(amounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.tree = tree
+	addr, tx, contract, err := Deploy(c.Transactor(t, 0), c, tokenAddr, tree)
+	if err != nil {
+		t.Fatalf("部署领取合约失败：%s", err)
+	}
+	if rp := c.Mine(t, tx); rp.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("部署领取合约失败")
+	}
+	e.addr, e.contract = addr, contract
+	tx, err = token.Transfer(c.Transactor(t, 0), addr, tree.Total())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Mine(t, tx)
+	return e
+}
+
+// send 由账户 i 发送交易并返回回执，指定 GasLimit 以便回滚的交易也能上链
+func (e *distributorEnv) send(t *testing.T, i int, fn func(opts *bind.TransactOpts) (*types.Transaction, error)) *types.Receipt {
+	t.Helper()
+	opts := e.chain.Transactor(t, i)
+	opts.GasLimit = 300000
+	tx, err := fn(opts)
+	if err != nil {
+		t.Fatalf("发送交易失败：%s", err)
+	}
+	return e.chain.Mine(t, tx)
+}
+
+func (e *distributorEnv) claim(t *testing.T, account common.Address, amount *big.Int, proof []common.Hash) *types.Receipt {
+	t.Helper()
+	return e.send(t, 1, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return e.contract.Claim(opts, account, amount, ProofArgs(proof))
+	})
+}
+
+func (e *distributorEnv) balance(t *testing.T, addr common.Address) int64 {
+	t.Helper()
+	v, err := e.token.BalanceOf(&bind.CallOpts{}, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v.Int64()
+}
+
+func TestDistributorClaim(t *testing.T) {
+	for _, quiet := range []bool{false, true} {
+		e := newDistributorEnv(t, quiet)
+		if root, _ := e.contract.MerkleRoot(&bind.CallOpts{}); root != e.tree.Root() {
+			t.Fatalf("合约中的树根 %x，预期 %s", root, e.tree.Root().Hex())
+		}
+		account := e.accounts[2]
+		amount, proof, _ := e.tree.Proof(account)
+		// 任何人都可以按证明替目标钱包领取，代币转给目标钱包
+		rp := e.claim(t, account, amount, proof)
+		if rp.Status != types.ReceiptStatusSuccessful {
+			t.Fatalf("quiet=%v：领取失败", quiet)
+		}
+		if got := e.balance(t, account); got != 300 {
+			t.Errorf("quiet=%v：领取后余额 %d，预期 300", quiet, got)
+		}
+		if got := e.balance(t, e.addr); got != 1200 {
+			t.Errorf("quiet=%v：领取后合约余额 %d，预期 1200", quiet, got)
+		}
+		if len(rp.Logs) == 0 {
+			t.Fatalf("quiet=%v：没有 Claimed 事件", quiet)
+		}
+		ev, err := e.contract.ParseClaimed(*rp.Logs[len(rp.Logs)-1])
+		if err != nil || ev.Account != account || ev.Amount.Cmp(amount) != 0 {
+			t.Errorf("quiet=%v：Claimed 事件 %+v, %v", quiet, ev, err)
+		}
+		if claimed, _ := e.contract.IsClaimed(&bind.CallOpts{}, account); !claimed {
+			t.Errorf("quiet=%v：领取后 isClaimed 应当为 true", quiet)
+		}
+		if claimed, _ := e.contract.IsClaimed(&bind.CallOpts{}, e.accounts[0]); claimed {
+			t.Errorf("quiet=%v：未领取的地址 isClaimed 应当为 false", quiet)
+		}
+	}
+}
+
+func TestDistributorClaimRevert(t *testing.T) {
+	e := newDistributorEnv(t, false)
+	account := e.accounts[1]
+	amount, proof, _ := e.tree.Proof(account)
+	_, other, _ := e.tree.Proof(e.accounts[3])
+	for _, c := range []struct {
+		name    string
+		account common.Address
+		amount  *big.Int
+		proof   []common.Hash
+	}{
+		{"数量错误", account, new(big.Int).Add(amount, big.NewInt(1)), proof},
+		{"使用其他地址的证明", account, amount, other},
+		{"证明为空", account, amount, nil},
+		{"不在树中的地址", common.HexToAddress("0xdead"), amount, proof},
+	} {
+		if rp := e.claim(t, c.account, c.amount, c.proof); rp.Status != types.ReceiptStatusFailed {
+			t.Errorf("%s：领取应当回滚", c.name)
+		}
+	}
+	if got := e.balance(t, e.addr); got != 1500 {
+		t.Fatalf("回滚后合约余额 %d，预期 1500", got)
+	}
+
+	// 每个地址只能领取一次
+	if rp := e.claim(t, account, amount, proof); rp.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("领取失败")
+	}
+	if rp := e.claim(t, account, amount, proof); rp.Status != types.ReceiptStatusFailed {
+		t.Error("重复领取应当回滚")
+	}
+	if got := e.balance(t, account); got != 200 {
+		t.Errorf("重复领取后余额 %d，预期 200", got)
+	}
+}
+
+func TestDistributorSweep(t *testing.T) {
+	e := newDistributorEnv(t, false)
+	owner, user := e.chain.Address(0), e.chain.Address(1)
+	if got, _ := e.contract.Owner(&bind.CallOpts{}); got != owner {
+		t.Fatalf("owner 为 %s，预期部署账户 %s", got.Hex(), owner.Hex())
+	}
+	amount, proof, _ := e.tree.Proof(e.accounts[4])
+	if rp := e.claim(t, e.accounts[4], amount, proof); rp.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("领取失败")
+	}
+
+	// 只有 owner 可以收回剩余的代币
+	if rp := e.send(t, 1, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return e.contract.Sweep(opts, user)
+	}); rp.Status != types.ReceiptStatusFailed {
+		t.Fatal("非 owner 调用 sweep 应当回滚")
+	}
+	before := e.balance(t, owner)
+	rp := e.send(t, 0, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return e.contract.Sweep(opts, owner)
+	})
+	if rp.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("owner 调用 sweep 失败")
+	}
+	if got := e.balance(t, owner) - before; got != 1000 {
+		t.Errorf("收回 %d 个代币，预期 1000", got)
+	}
+	if got := e.balance(t, e.addr); got != 0 {
+		t.Errorf("收回后合约余额 %d，预期 0", got)
+	}
+	ev, err := e.contract.ParseSwept(*rp.Logs[len(rp.Logs)-1])
+	if err != nil || ev.To != owner || ev.Amount.Int64() != 1000 {
+		t.Errorf("Swept 事件 %+v, %v", ev, err)
+	}
+
+	// 收回后合约没有代币，领取失败且不会标记为已领取
+	amount, proof, _ = e.tree.Proof(e.accounts[0])
+	if rp := e.claim(t, e.accounts[0], amount, proof); rp.Status != types.ReceiptStatusFailed {
+		t.Error("收回后领取应当回滚")
+	}
+	if claimed, _ := e.contract.IsClaimed(&bind.CallOpts{}, e.accounts[0]); claimed {
+		t.Error("领取回滚后 isClaimed 应当为 false")
+	}
+}
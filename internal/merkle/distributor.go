@@ -0,0 +1,658 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package merkle
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
+
+// MerkleDistributorMetaData contains all meta data concerning the MerkleDistributor contract.
+var MerkleDistributorMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"name\":\"token\",\"type\":\"address\"},{\"name\":\"merkleRoot\",\"type\":\"bytes32\"},{\"name\":\"owner\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"constant\":true,\"inputs\":[],\"name\":\"token\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"merkleRoot\",\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"account\",\"type\":\"address\"}],\"name\":\"isClaimed\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"account\",\"type\":\"address\"},{\"name\":\"amount\",\"type\":\"uint256\"},{\"name\":\"merkleProof\",\"type\":\"bytes32[]\"}],\"name\":\"claim\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"to\",\"type\":\"address\"}],\"name\":\"sweep\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"account\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"Claimed\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"Swept\",\"type\":\"event\"}]",
+	Bin: "0x6100608038036000396100005161000055610020516100015561004051610002556102648061002e6000396000f3346300000078576000357c0100000000000000000000000000000000000000000000000000000000900480633d13f8741463000000b85780638cc080251463000000a1578063fc0c546a14630000007d5780632eb4a7ab1463000000895780638da5cb5b14630000009557806301681a621463000001b8575b600080fd5b60005460005260206000f35b60015460005260206000f35b60025460005260206000f35b600435600052602060002054151560005260206000f35b6004358060005260206000208054630000007857816000526024356020526040600020600052602060002060443560040180359060200160005b828110156300000132578060200282013584818110630000011a576020526000526300000121565b6000526020525b6040600020935060010163000000f2565b50505060015414156300000078576001905563a9059cbb60005280602052602435604052602060006044601c60006000545af1156300000078573d1563000001895760203d10630000007857600051156300000078575b602435600052807fd8138f8a3f377c5259ca548e70e4c2de94f129f5a11036a15b69513cba2b426a60206000a2005b6002543314156300000078576370a0823160005230602052602060006024601c6000545afa1563000000785760203d1063000000785760005163a9059cbb60005260043560205280604052602060006044601c60006000545af1156300000078573d1563000002365760203d10630000007857600051156300000078575b6000526004357fc36b5179cb9c303b200074996eab2b3473eac370fdd7eba3bec636fe3510969660206000a200",
+}
+
+// MerkleDistributorABI is the input ABI used to generate the binding from.
+// Deprecated: Use MerkleDistributorMetaData.ABI instead.
+var MerkleDistributorABI = MerkleDistributorMetaData.ABI
+
+// MerkleDistributorBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use MerkleDistributorMetaData.Bin instead.
+var MerkleDistributorBin = MerkleDistributorMetaData.Bin
+
+// DeployMerkleDistributor deploys a new Ethereum contract, binding an instance of MerkleDistributor to it.
+func DeployMerkleDistributor(auth *bind.TransactOpts, backend bind.ContractBackend, token common.Address, merkleRoot [32]byte, owner common.Address) (common.Address, *types.Transaction, *MerkleDistributor, error) {
+	parsed, err := MerkleDistributorMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(MerkleDistributorBin), backend, token, merkleRoot, owner)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &MerkleDistributor{MerkleDistributorCaller: MerkleDistributorCaller{contract: contract}, MerkleDistributorTransactor: MerkleDistributorTransactor{contract: contract}, MerkleDistributorFilterer: MerkleDistributorFilterer{contract: contract}}, nil
+}
+
+// MerkleDistributor is an auto generated Go binding around an Ethereum contract.
+type MerkleDistributor struct {
+	MerkleDistributorCaller     // Read-only binding to the contract
+	MerkleDistributorTransactor // Write-only binding to the contract
+	MerkleDistributorFilterer   // Log filterer for contract events
+}
+
+// MerkleDistributorCaller is an auto generated read-only Go binding around an Ethereum contract.
+type MerkleDistributorCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MerkleDistributorTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type MerkleDistributorTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MerkleDistributorFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type MerkleDistributorFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MerkleDistributorSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type MerkleDistributorSession struct {
+	Contract     *MerkleDistributor // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts      // Call options to use throughout this session
+	TransactOpts bind.TransactOpts  // Transaction auth options to use throughout this session
+}
+
+// MerkleDistributorCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type MerkleDistributorCallerSession struct {
+	Contract *MerkleDistributorCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts            // Call options to use throughout this session
+}
+
+// MerkleDistributorTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type MerkleDistributorTransactorSession struct {
+	Contract     *MerkleDistributorTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts            // Transaction auth options to use throughout this session
+}
+
+// MerkleDistributorRaw is an auto generated low-level Go binding around an Ethereum contract.
+type MerkleDistributorRaw struct {
+	Contract *MerkleDistributor // Generic contract binding to access the raw methods on
+}
+
+// MerkleDistributorCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type MerkleDistributorCallerRaw struct {
+	Contract *MerkleDistributorCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// MerkleDistributorTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type MerkleDistributorTransactorRaw struct {
+	Contract *MerkleDistributorTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewMerkleDistributor creates a new instance of MerkleDistributor, bound to a specific deployed contract.
+func NewMerkleDistributor(address common.Address, backend bind.ContractBackend) (*MerkleDistributor, error) {
+	contract, err := bindMerkleDistributor(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &MerkleDistributor{MerkleDistributorCaller: MerkleDistributorCaller{contract: contract}, MerkleDistributorTransactor: MerkleDistributorTransactor{contract: contract}, MerkleDistributorFilterer: MerkleDistributorFilterer{contract: contract}}, nil
+}
+
+// NewMerkleDistributorCaller creates a new read-only instance of MerkleDistributor, bound to a specific deployed contract.
+func NewMerkleDistributorCaller(address common.Address, caller bind.ContractCaller) (*MerkleDistributorCaller, error) {
+	contract, err := bindMerkleDistributor(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MerkleDistributorCaller{contract: contract}, nil
+}
+
+// NewMerkleDistributorTransactor creates a new write-only instance of MerkleDistributor, bound to a specific deployed contract.
+func NewMerkleDistributorTransactor(address common.Address, transactor bind.ContractTransactor) (*MerkleDistributorTransactor, error) {
+	contract, err := bindMerkleDistributor(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MerkleDistributorTransactor{contract: contract}, nil
+}
+
+// NewMerkleDistributorFilterer creates a new log filterer instance of MerkleDistributor, bound to a specific deployed contract.
+func NewMerkleDistributorFilterer(address common.Address, filterer bind.ContractFilterer) (*MerkleDistributorFilterer, error) {
+	contract, err := bindMerkleDistributor(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &MerkleDistributorFilterer{contract: contract}, nil
+}
+
+// bindMerkleDistributor binds a generic wrapper to an already deployed contract.
+func bindMerkleDistributor(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(MerkleDistributorABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_MerkleDistributor *MerkleDistributorRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _MerkleDistributor.Contract.MerkleDistributorCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_MerkleDistributor *MerkleDistributorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _MerkleDistributor.Contract.MerkleDistributorTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_MerkleDistributor *MerkleDistributorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _MerkleDistributor.Contract.MerkleDistributorTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_MerkleDistributor *MerkleDistributorCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _MerkleDistributor.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_MerkleDistributor *MerkleDistributorTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _MerkleDistributor.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_MerkleDistributor *MerkleDistributorTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _MerkleDistributor.Contract.contract.Transact(opts, method, params...)
+}
+
+// IsClaimed is a free data retrieval call binding the contract method 0x8cc08025.
+//
+// Solidity: function isClaimed(address account) view returns(bool)
+func (_MerkleDistributor *MerkleDistributorCaller) IsClaimed(opts *bind.CallOpts, account common.Address) (bool, error) {
+	var out []interface{}
+	err := _MerkleDistributor.contract.Call(opts, &out, "isClaimed", account)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// IsClaimed is a free data retrieval call binding the contract method 0x8cc08025.
+//
+// Solidity: function isClaimed(address account) view returns(bool)
+func (_MerkleDistributor *MerkleDistributorSession) IsClaimed(account common.Address) (bool, error) {
+	return _MerkleDistributor.Contract.IsClaimed(&_MerkleDistributor.CallOpts, account)
+}
+
+// IsClaimed is a free data retrieval call binding the contract method 0x8cc08025.
+//
+// Solidity: function isClaimed(address account) view returns(bool)
+func (_MerkleDistributor *MerkleDistributorCallerSession) IsClaimed(account common.Address) (bool, error) {
+	return _MerkleDistributor.Contract.IsClaimed(&_MerkleDistributor.CallOpts, account)
+}
+
+// MerkleRoot is a free data retrieval call binding the contract method 0x2eb4a7ab.
+//
+// Solidity: function merkleRoot() view returns(bytes32)
+func (_MerkleDistributor *MerkleDistributorCaller) MerkleRoot(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _MerkleDistributor.contract.Call(opts, &out, "merkleRoot")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// MerkleRoot is a free data retrieval call binding the contract method 0x2eb4a7ab.
+//
+// Solidity: function merkleRoot() view returns(bytes32)
+func (_MerkleDistributor *MerkleDistributorSession) MerkleRoot() ([32]byte, error) {
+	return _MerkleDistributor.Contract.MerkleRoot(&_MerkleDistributor.CallOpts)
+}
+
+// MerkleRoot is a free data retrieval call binding the contract method 0x2eb4a7ab.
+//
+// Solidity: function merkleRoot() view returns(bytes32)
+func (_MerkleDistributor *MerkleDistributorCallerSession) MerkleRoot() ([32]byte, error) {
+	return _MerkleDistributor.Contract.MerkleRoot(&_MerkleDistributor.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_MerkleDistributor *MerkleDistributorCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _MerkleDistributor.contract.Call(opts, &out, "owner")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_MerkleDistributor *MerkleDistributorSession) Owner() (common.Address, error) {
+	return _MerkleDistributor.Contract.Owner(&_MerkleDistributor.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_MerkleDistributor *MerkleDistributorCallerSession) Owner() (common.Address, error) {
+	return _MerkleDistributor.Contract.Owner(&_MerkleDistributor.CallOpts)
+}
+
+// Token is a free data retrieval call binding the contract method 0xfc0c546a.
+//
+// Solidity: function token() view returns(address)
+func (_MerkleDistributor *MerkleDistributorCaller) Token(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _MerkleDistributor.contract.Call(opts, &out, "token")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Token is a free data retrieval call binding the contract method 0xfc0c546a.
+//
+// Solidity: function token() view returns(address)
+func (_MerkleDistributor *MerkleDistributorSession) Token() (common.Address, error) {
+	return _MerkleDistributor.Contract.Token(&_MerkleDistributor.CallOpts)
+}
+
+// Token is a free data retrieval call binding the contract method 0xfc0c546a.
+//
+// Solidity: function token() view returns(address)
+func (_MerkleDistributor *MerkleDistributorCallerSession) Token() (common.Address, error) {
+	return _MerkleDistributor.Contract.Token(&_MerkleDistributor.CallOpts)
+}
+
+// Claim is a paid mutator transaction binding the contract method 0x3d13f874.
+//
+// Solidity: function claim(address account, uint256 amount, bytes32[] merkleProof) returns()
+func (_MerkleDistributor *MerkleDistributorTransactor) Claim(opts *bind.TransactOpts, account common.Address, amount *big.Int, merkleProof [][32]byte) (*types.Transaction, error) {
+	return _MerkleDistributor.contract.Transact(opts, "claim", account, amount, merkleProof)
+}
+
+// Claim is a paid mutator transaction binding the contract method 0x3d13f874.
+//
+// Solidity: function claim(address account, uint256 amount, bytes32[] merkleProof) returns()
+func (_MerkleDistributor *MerkleDistributorSession) Claim(account common.Address, amount *big.Int, merkleProof [][32]byte) (*types.Transaction, error) {
+	return _MerkleDistributor.Contract.Claim(&_MerkleDistributor.TransactOpts, account, amount, merkleProof)
+}
+
+// Claim is a paid mutator transaction binding the contract method 0x3d13f874.
+//
+// Solidity: function claim(address account, uint256 amount, bytes32[] merkleProof) returns()
+func (_MerkleDistributor *MerkleDistributorTransactorSession) Claim(account common.Address, amount *big.Int, merkleProof [][32]byte) (*types.Transaction, error) {
+	return _MerkleDistributor.Contract.Claim(&_MerkleDistributor.TransactOpts, account, amount, merkleProof)
+}
+
+// Sweep is a paid mutator transaction binding the contract method 0x01681a62.
+//
+// Solidity: function sweep(address to) returns()
+func (_MerkleDistributor *MerkleDistributorTransactor) Sweep(opts *bind.TransactOpts, to common.Address) (*types.Transaction, error) {
+	return _MerkleDistributor.contract.Transact(opts, "sweep", to)
+}
+
+// Sweep is a paid mutator transaction binding the contract method 0x01681a62.
+//
+// Solidity: function sweep(address to) returns()
+func (_MerkleDistributor *MerkleDistributorSession) Sweep(to common.Address) (*types.Transaction, error) {
+	return _MerkleDistributor.Contract.Sweep(&_MerkleDistributor.TransactOpts, to)
+}
+
+// Sweep is a paid mutator transaction binding the contract method 0x01681a62.
+//
+// Solidity: function sweep(address to) returns()
+func (_MerkleDistributor *MerkleDistributorTransactorSession) Sweep(to common.Address) (*types.Transaction, error) {
+	return _MerkleDistributor.Contract.Sweep(&_MerkleDistributor.TransactOpts, to)
+}
+
+// MerkleDistributorClaimedIterator is returned from FilterClaimed and is used to iterate over the raw logs and unpacked data for Claimed events raised by the MerkleDistributor contract.
+type MerkleDistributorClaimedIterator struct {
+	Event *MerkleDistributorClaimed // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MerkleDistributorClaimedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MerkleDistributorClaimed)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MerkleDistributorClaimed)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MerkleDistributorClaimedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MerkleDistributorClaimedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MerkleDistributorClaimed represents a Claimed event raised by the MerkleDistributor contract.
+type MerkleDistributorClaimed struct {
+	Account common.Address
+	Amount  *big.Int
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterClaimed is a free log retrieval operation binding the contract event 0xd8138f8a3f377c5259ca548e70e4c2de94f129f5a11036a15b69513cba2b426a.
+//
+// Solidity: event Claimed(address indexed account, uint256 amount)
+func (_MerkleDistributor *MerkleDistributorFilterer) FilterClaimed(opts *bind.FilterOpts, account []common.Address) (*MerkleDistributorClaimedIterator, error) {
+
+	var accountRule []interface{}
+	for _, accountItem := range account {
+		accountRule = append(accountRule, accountItem)
+	}
+
+	logs, sub, err := _MerkleDistributor.contract.FilterLogs(opts, "Claimed", accountRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MerkleDistributorClaimedIterator{contract: _MerkleDistributor.contract, event: "Claimed", logs: logs, sub: sub}, nil
+}
+
+// WatchClaimed is a free log subscription operation binding the contract event 0xd8138f8a3f377c5259ca548e70e4c2de94f129f5a11036a15b69513cba2b426a.
+//
+// Solidity: event Claimed(address indexed account, uint256 amount)
+func (_MerkleDistributor *MerkleDistributorFilterer) WatchClaimed(opts *bind.WatchOpts, sink chan<- *MerkleDistributorClaimed, account []common.Address) (event.Subscription, error) {
+
+	var accountRule []interface{}
+	for _, accountItem := range account {
+		accountRule = append(accountRule, accountItem)
+	}
+
+	logs, sub, err := _MerkleDistributor.contract.WatchLogs(opts, "Claimed", accountRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MerkleDistributorClaimed)
+				if err := _MerkleDistributor.contract.UnpackLog(event, "Claimed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseClaimed is a log parse operation binding the contract event 0xd8138f8a3f377c5259ca548e70e4c2de94f129f5a11036a15b69513cba2b426a.
+//
+// Solidity: event Claimed(address indexed account, uint256 amount)
+func (_MerkleDistributor *MerkleDistributorFilterer) ParseClaimed(log types.Log) (*MerkleDistributorClaimed, error) {
+	event := new(MerkleDistributorClaimed)
+	if err := _MerkleDistributor.contract.UnpackLog(event, "Claimed", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// MerkleDistributorSweptIterator is returned from FilterSwept and is used to iterate over the raw logs and unpacked data for Swept events raised by the MerkleDistributor contract.
+type MerkleDistributorSweptIterator struct {
+	Event *MerkleDistributorSwept // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MerkleDistributorSweptIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MerkleDistributorSwept)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MerkleDistributorSwept)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MerkleDistributorSweptIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MerkleDistributorSweptIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MerkleDistributorSwept represents a Swept event raised by the MerkleDistributor contract.
+type MerkleDistributorSwept struct {
+	To     common.Address
+	Amount *big.Int
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterSwept is a free log retrieval operation binding the contract event 0xc36b5179cb9c303b200074996eab2b3473eac370fdd7eba3bec636fe35109696.
+//
+// Solidity: event Swept(address indexed to, uint256 amount)
+func (_MerkleDistributor *MerkleDistributorFilterer) FilterSwept(opts *bind.FilterOpts, to []common.Address) (*MerkleDistributorSweptIterator, error) {
+
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _MerkleDistributor.contract.FilterLogs(opts, "Swept", toRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MerkleDistributorSweptIterator{contract: _MerkleDistributor.contract, event: "Swept", logs: logs, sub: sub}, nil
+}
+
+// WatchSwept is a free log subscription operation binding the contract event 0xc36b5179cb9c303b200074996eab2b3473eac370fdd7eba3bec636fe35109696.
+//
+// Solidity: event Swept(address indexed to, uint256 amount)
+func (_MerkleDistributor *MerkleDistributorFilterer) WatchSwept(opts *bind.WatchOpts, sink chan<- *MerkleDistributorSwept, to []common.Address) (event.Subscription, error) {
+
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _MerkleDistributor.contract.WatchLogs(opts, "Swept", toRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MerkleDistributorSwept)
+				if err := _MerkleDistributor.contract.UnpackLog(event, "Swept", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseSwept is a log parse operation binding the contract event 0xc36b5179cb9c303b200074996eab2b3473eac370fdd7eba3bec636fe35109696.
+//
+// Solidity: event Swept(address indexed to, uint256 amount)
+func (_MerkleDistributor *MerkleDistributorFilterer) ParseSwept(log types.Log) (*MerkleDistributorSwept, error) {
+	event := new(MerkleDistributorSwept)
+	if err := _MerkleDistributor.contract.UnpackLog(event, "Swept", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -0,0 +1,145 @@
+// Package merkle 生成与 OpenZeppelin StandardMerkleTree 兼容的空投 Merkle 树，
+// 并包含领取合约 MerkleDistributor 的 Go 绑定，合约源码见 internal/contracts/MerkleDistributor.easm
+package merkle
+
+//go:generate sh -c "cd ../contracts && go run gen.go"
+//go:generate abigen --abi ../contracts/MerkleDistributor.abi --bin ../contracts/MerkleDistributor.bin --pkg merkle --type MerkleDistributor --out distributor.go
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// LeafHash 计算 (account, amount) 的叶子：keccak256(keccak256(abi.encode(account, amount)))
+func LeafHash(account common.Address, amount *big.Int) common.Hash {
+	inner := crypto.Keccak256(common.LeftPadBytes(account.Bytes(), 32), common.LeftPadBytes(amount.Bytes(), 32))
+	return crypto.Keccak256Hash(inner)
+}
+
+// hashPair 与 OpenZeppelin MerkleProof 一致，较小的哈希在前
+func hashPair(a, b common.Hash) common.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(a[:], b[:])
+}
+
+// Verify 校验证明，与合约中的验证逻辑相同
+func Verify(root, leaf common.Hash, proof []common.Hash) bool {
+	for _, p := range proof {
+		leaf = hashPair(leaf, p)
+	}
+	return leaf == root
+}
+
+// Tree 按 OpenZeppelin StandardMerkleTree 的方式存放的完全二叉树，nodes[0] 为树根
+type Tree struct {
+	nodes   []common.Hash
+	indexes map[common.Address]int
+	amounts map[common.Address]*big.Int
+}
+
+// NewTree 用每个地址的领取数量构建 Merkle 树
+func NewTree(amounts map[common.Address]*big.Int) (*Tree, error) {
+	if len(amounts) == 0 {
+		return nil, errors.New("merkle: no leaves")
+	}
+	type leaf struct {
+		account common.Address
+		hash    common.Hash
+	}
+	leaves := make([]leaf, 0, len(amounts))
+	for account, amount := range amounts {
+		if amount == nil || amount.Sign() <= 0 || amount.BitLen() > 256 {
+			return nil, fmt.Errorf("merkle: invalid amount for %s: %v", account.Hex(), amount)
+		}
+		leaves = append(leaves, leaf{account, LeafHash(account, amount)})
+	}
+	sort.Slice(leaves, func(i, j int) bool {
+		return bytes.Compare(leaves[i].hash[:], leaves[j].hash[:]) < 0
+	})
+
+	t := &Tree{
+		nodes:   make([]common.Hash, 2*len(leaves)-1),
+		indexes: make(map[common.Address]int, len(leaves)),
+		amounts: make(map[common.Address]*big.Int, len(leaves)),
+	}
+	for i, l := range leaves {
+		index := len(t.nodes) - 1 - i
+		t.nodes[index] = l.hash
+		t.indexes[l.account] = index
+		t.amounts[l.account] = new(big.Int).Set(amounts[l.account])
+	}
+	for i := len(t.nodes) - 1 - len(leaves); i >= 0; i-- {
+		t.nodes[i] = hashPair(t.nodes[2*i+1], t.nodes[2*i+2])
+	}
+	return t, nil
+}
+
+// Root 返回树根
+func (t *Tree) Root() common.Hash {
+	return t.nodes[0]
+}
+
+// Proof 返回地址的领取数量和证明
+func (t *Tree) Proof(account common.Address) (*big.Int, []common.Hash, bool) {
+	index, has := t.indexes[account]
+	if !has {
+		return nil, nil, false
+	}
+	proof := make([]common.Hash, 0)
+	for index > 0 {
+		// 奇数下标的兄弟在右边，偶数在左边
+		if index%2 == 1 {
+			proof = append(proof, t.nodes[index+1])
+		} else {
+			proof = append(proof, t.nodes[index-1])
+		}
+		index = (index - 1) / 2
+	}
+	return new(big.Int).Set(t.amounts[account]), proof, true
+}
+
+// Total 返回所有地址的领取总量，即需要转入领取合约的代币数
+func (t *Tree) Total() *big.Int {
+	total := new(big.Int)
+	for _, amount := range t.amounts {
+		total.Add(total, amount)
+	}
+	return total
+}
+
+// Claim 一个地址的领取信息
+type Claim struct {
+	Amount string        `json:"amount"`
+	Proof  []common.Hash `json:"proof"`
+}
+
+// Distribution 提供给领取页面的证明文件
+type Distribution struct {
+	MerkleRoot   common.Hash      `json:"merkleRoot"`
+	TokenTotal   string           `json:"tokenTotal"`
+	LeafEncoding []string         `json:"leafEncoding"`
+	Claims       map[string]Claim `json:"claims"`
+}
+
+// Distribution 导出所有地址的证明
+func (t *Tree) Distribution() *Distribution {
+	d := &Distribution{
+		MerkleRoot:   t.Root(),
+		TokenTotal:   t.Total().String(),
+		LeafEncoding: []string{"address", "uint256"},
+		Claims:       make(map[string]Claim, len(t.indexes)),
+	}
+	for account := range t.indexes {
+		amount, proof, _ := t.Proof(account)
+		d.Claims[account.Hex()] = Claim{Amount: amount.String(), Proof: proof}
+	}
+	return d
+}
@@ -0,0 +1,75 @@
+package merkle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func bigInt(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic(s)
+	}
+	return v
+}
+
+// OpenZeppelin @openzeppelin/merkle-tree README 中 StandardMerkleTree.of 的示例
+func TestTreeOpenZeppelinVector(t *testing.T) {
+	a := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	b := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	tree, err := NewTree(map[common.Address]*big.Int{
+		a: bigInt("5000000000000000000"),
+		b: bigInt("2500000000000000000"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := common.HexToHash("0xd4dee0beab2d53f2cc83e567171bd2820e49898130a22622b10ead383e90bd77"); tree.Root() != want {
+		t.Fatalf("树根 %s，预期 %s", tree.Root().Hex(), want.Hex())
+	}
+	// 两个叶子时证明就是另一个叶子
+	amount, proof, ok := tree.Proof(a)
+	if !ok || amount.String() != "5000000000000000000" || len(proof) != 1 || proof[0] != LeafHash(b, bigInt("2500000000000000000")) {
+		t.Fatalf("%s 的证明 %v %v", a.Hex(), amount, proof)
+	}
+	if tree.Total().String() != "7500000000000000000" {
+		t.Fatalf("总量 %s", tree.Total())
+	}
+}
+
+func TestTreeProofs(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 8, 13} {
+		amounts := make(map[common.Address]*big.Int)
+		for i := 0; i < n; i++ {
+			amounts[common.BigToAddress(big.NewInt(int64(0x100+i)))] = big.NewInt(int64(1000 * (i + 1)))
+		}
+		tree, err := NewTree(amounts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for account, amount := range amounts {
+			got, proof, ok := tree.Proof(account)
+			if !ok || got.Cmp(amount) != 0 {
+				t.Fatalf("%d 个叶子：%s 的数量 %v，预期 %s", n, account.Hex(), got, amount)
+			}
+			if !Verify(tree.Root(), LeafHash(account, amount), proof) {
+				t.Errorf("%d 个叶子：%s 的证明无效", n, account.Hex())
+			}
+			// 证明与数量绑定，改动数量后不能通过验证
+			if Verify(tree.Root(), LeafHash(account, new(big.Int).Add(amount, big.NewInt(1))), proof) {
+				t.Errorf("%d 个叶子：%s 改动数量后证明仍然有效", n, account.Hex())
+			}
+		}
+		if _, _, ok := tree.Proof(common.Address{}); ok {
+			t.Errorf("%d 个叶子：不在树中的地址不应当有证明", n)
+		}
+	}
+	if _, err := NewTree(nil); err == nil {
+		t.Error("没有叶子时应当返回错误")
+	}
+	if _, err := NewTree(map[common.Address]*big.Int{{}: big.NewInt(0)}); err == nil {
+		t.Error("数量为 0 时应当返回错误")
+	}
+}
@@ -0,0 +1,273 @@
+// Package netconf 网络配置：读写 networks.json 中的节点地址、链 ID、浏览器与交易类型，按配置连接节点
+package netconf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/naiba/eth-tools/internal/ethutil"
+)
+
+// Profile 一个网络的配置
+type Profile struct {
+	Name     string          `json:"name"`
+	RPC      []string        `json:"rpc"`                // 节点地址，连接时按顺序尝试
+	ChainID  uint64          `json:"chain_id"`           // 预期的链 ID
+	Explorer string          `json:"explorer,omitempty"` // 浏览器交易链接模板，{tx} 替换为交易哈希
+	Symbol   string          `json:"symbol"`             // 原生币符号
+	Fee      ethutil.FeeMode `json:"fee"`                // 默认交易类型
+}
+
+// Config 网络配置文件，App 之间共用
+type Config struct {
+	Profiles []Profile `json:"profiles"`
+
+	path string
+}
+
+// defaults 配置文件不存在时使用的网络，都是不需要 API Key 的公共节点
+var defaults = []Profile{
+	{
+		Name:     "Mainnet",
+		RPC:      []string{"wss://ethereum-rpc.publicnode.com", "https://ethereum-rpc.publicnode.com"},
+		ChainID:  1,
+		Explorer: "https://etherscan.io/tx/{tx}",
+		Symbol:   "ETH",
+		Fee:      ethutil.FeeDynamic,
+	},
+	{
+		Name:     "Sepolia",
+		RPC:      []string{"wss://ethereum-sepolia-rpc.publicnode.com", "https://ethereum-sepolia-rpc.publicnode.com"},
+		ChainID:  11155111,
+		Explorer: "https://sepolia.etherscan.io/tx/{tx}",
+		Symbol:   "ETH",
+		Fee:      ethutil.FeeDynamic,
+	},
+	{
+		Name:     "Holesky",
+		RPC:      []string{"wss://ethereum-holesky-rpc.publicnode.com", "https://ethereum-holesky-rpc.publicnode.com"},
+		ChainID:  17000,
+		Explorer: "https://holesky.etherscan.io/tx/{tx}",
+		Symbol:   "ETH",
+		Fee:      ethutil.FeeDynamic,
+	},
+	{
+		Name:    "Local",
+		RPC:     []string{"ws://127.0.0.1:8546", "http://127.0.0.1:8545"},
+		ChainID: 1337,
+		Symbol:  "ETH",
+		Fee:     ethutil.FeeAuto,
+	},
+}
+
+// DefaultPath 默认的配置文件位置：用户配置目录下的 eth-tools/networks.json
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "networks.json"
+	}
+	return filepath.Join(dir, "eth-tools", "networks.json")
+}
+
+// Load 读取配置文件，path 为空时使用 DefaultPath，文件不存在时使用内置的网络
+func Load(path string) (*Config, error) {
+	if path == "" {
+		path = DefaultPath()
+	}
+	cfg := &Config{path: path}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		cfg.Profiles = append([]Profile(nil), defaults...)
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取网络配置失败：%s", err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("解析网络配置失败：%s", err)
+	}
+	for i := range cfg.Profiles {
+		if err := cfg.Profiles[i].normalize(); err != nil {
+			return nil, fmt.Errorf("网络配置 %s 有误：%s", path, err)
+		}
+	}
+	if len(cfg.Profiles) == 0 {
+		return nil, fmt.Errorf("网络配置 %s 中没有网络", path)
+	}
+	return cfg, nil
+}
+
+// Path 配置文件的位置
+func (c *Config) Path() string {
+	return c.path
+}
+
+// Save 写回配置文件，先写临时文件再替换，避免写到一半损坏配置
+func (c *Config) Save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("保存网络配置失败：%s", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("保存网络配置失败：%s", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("保存网络配置失败：%s", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("保存网络配置失败：%s", err)
+	}
+	return nil
+}
+
+// Names 所有网络的名称，顺序与配置文件一致
+func (c *Config) Names() []string {
+	names := make([]string, len(c.Profiles))
+	for i, p := range c.Profiles {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// Find 按名称查找网络，不区分大小写
+func (c *Config) Find(name string) (Profile, bool) {
+	for _, p := range c.Profiles {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// Put 校验后添加网络，已有同名网络时替换
+func (c *Config) Put(p Profile) error {
+	if err := p.normalize(); err != nil {
+		return err
+	}
+	for i := range c.Profiles {
+		if strings.EqualFold(c.Profiles[i].Name, p.Name) {
+			c.Profiles[i] = p
+			return nil
+		}
+	}
+	c.Profiles = append(c.Profiles, p)
+	return nil
+}
+
+// Delete 删除网络，至少保留一个
+func (c *Config) Delete(name string) error {
+	for i := range c.Profiles {
+		if !strings.EqualFold(c.Profiles[i].Name, name) {
+			continue
+		}
+		if len(c.Profiles) == 1 {
+			return errors.New("至少需要保留一个网络")
+		}
+		c.Profiles = append(c.Profiles[:i], c.Profiles[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("没有名为 %s 的网络", name)
+}
+
+// normalize 校验网络配置，并整理节点地址和交易类型的写法
+func (p *Profile) normalize() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return errors.New("网络名称不能为空")
+	}
+	var urls []string
+	for _, u := range p.RPC {
+		if u = strings.TrimSpace(u); u == "" {
+			continue
+		}
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return fmt.Errorf("%s 的节点地址 %s 有误：%s", p.Name, u, err)
+		}
+		switch parsed.Scheme {
+		case "http", "https", "ws", "wss":
+		default:
+			return fmt.Errorf("%s 的节点地址 %s 有误：只支持 http、https、ws、wss", p.Name, u)
+		}
+		urls = append(urls, u)
+	}
+	if len(urls) == 0 {
+		return fmt.Errorf("%s 没有节点地址", p.Name)
+	}
+	p.RPC = urls
+	if p.ChainID == 0 {
+		return fmt.Errorf("%s 没有设置链 ID", p.Name)
+	}
+	p.Explorer = strings.TrimSpace(p.Explorer)
+	if p.Explorer != "" && !strings.Contains(p.Explorer, "{tx}") {
+		return fmt.Errorf("%s 的浏览器链接模板中没有 {tx}", p.Name)
+	}
+	if p.Symbol = strings.TrimSpace(p.Symbol); p.Symbol == "" {
+		p.Symbol = "ETH"
+	}
+	mode, err := ethutil.ParseFeeMode(string(p.Fee))
+	if err != nil {
+		return fmt.Errorf("%s：%s", p.Name, err)
+	}
+	p.Fee = mode
+	return nil
+}
+
+// TxURL 交易在浏览器中的链接，没有配置浏览器时返回空字符串
+func (p Profile) TxURL(hash string) string {
+	if p.Explorer == "" {
+		return ""
+	}
+	return strings.Replace(p.Explorer, "{tx}", hash, -1)
+}
+
+// Dial 按顺序尝试各个节点地址，返回第一个能正常响应的节点
+func (p Profile) Dial(ctx context.Context) (*rpc.Client, string, error) {
+	var errs []string
+	for _, u := range p.RPC {
+		rc, err := rpc.DialContext(ctx, u)
+		if err == nil {
+			// HTTP 连接不会立即建立，调用一次确认节点可用
+			var chainID hexutil.Big
+			if err = rc.CallContext(ctx, &chainID, "eth_chainId"); err == nil {
+				return rc, u, nil
+			}
+			rc.Close()
+		}
+		errs = append(errs, fmt.Sprintf("%s：%s", u, err))
+	}
+	if len(errs) == 0 {
+		return nil, "", fmt.Errorf("%s 没有节点地址", p.Name)
+	}
+	return nil, "", fmt.Errorf("连接 %s 失败：%s", p.Name, strings.Join(errs, "；"))
+}
+
+// DialRetry 连接失败时每秒重试一次，最多尝试 attempts 次（至少一次），ctx 取消时立即返回。
+// 每次重试前调用 onRetry，可以为 nil。
+func (p Profile) DialRetry(ctx context.Context, attempts int, onRetry func(err error)) (*rpc.Client, string, error) {
+	for i := 1; ; i++ {
+		rc, url, err := p.Dial(ctx)
+		if err == nil || i >= attempts || ctx.Err() != nil {
+			return rc, url, err
+		}
+		if onRetry != nil {
+			onRetry(err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, "", err
+		case <-time.After(time.Second):
+		}
+	}
+}
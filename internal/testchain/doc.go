@@ -0,0 +1,5 @@
+// Package testchain 测试用的模拟链与 ERC-20 代币，代币源码见 TestToken.easm
+package testchain
+
+//go:generate go run ../contracts/gen.go
+//go:generate abigen --abi TestToken.abi --bin TestToken.bin --pkg testchain --type TestToken --out testtoken.go
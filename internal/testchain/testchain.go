@@ -0,0 +1,122 @@
+package testchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ChainID 模拟链的链 ID
+const ChainID = 1337
+
+// Chain 模拟链，补上 SimulatedBackend 缺少的 ChainID 后实现 ethutil.Backend
+type Chain struct {
+	*backends.SimulatedBackend
+	Keys []*ecdsa.PrivateKey // 创世时各有 100 ETH 的账户
+}
+
+// New 创建有 accounts 个账户的模拟链，测试结束时关闭
+func New(t testing.TB, accounts int) *Chain {
+	t.Helper()
+	c := &Chain{}
+	alloc := make(core.GenesisAlloc)
+	for i := 0; i < accounts; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Keys = append(c.Keys, key)
+		alloc[crypto.PubkeyToAddress(key.PublicKey)] = core.GenesisAccount{Balance: new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))}
+	}
+	c.SimulatedBackend = backends.NewSimulatedBackend(alloc, 30000000)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// ChainID 模拟链的链 ID
+func (c *Chain) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(ChainID), nil
+}
+
+// Address 第 i 个账户的地址
+func (c *Chain) Address(i int) common.Address {
+	return crypto.PubkeyToAddress(c.Keys[i].PublicKey)
+}
+
+// AutoCommit 每隔 interval 出一个块，直到测试结束
+func (c *Chain) AutoCommit(t testing.TB, interval time.Duration) {
+	stop, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				c.Commit()
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(stop)
+		<-done
+	})
+}
+
+// Transactor 第 i 个账户的交易参数
+func (c *Chain) Transactor(t testing.TB, i int) *bind.TransactOpts {
+	t.Helper()
+	opts, err := bind.NewKeyedTransactorWithChainID(c.Keys[i], big.NewInt(ChainID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return opts
+}
+
+// Mine 出块并返回交易的回执
+func (c *Chain) Mine(t testing.TB, tx *types.Transaction) *types.Receipt {
+	t.Helper()
+	c.Commit()
+	rp, err := c.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatalf("查询交易回执失败：%s", err)
+	}
+	return rp
+}
+
+// DeployToken 由第 i 个账户部署测试代币，quiet 为真时转账不返回数据
+func (c *Chain) DeployToken(t testing.TB, i int, quiet bool) (common.Address, *TestToken) {
+	t.Helper()
+	addr, tx, token, err := DeployTestToken(c.Transactor(t, i), c, quiet)
+	if err != nil {
+		t.Fatalf("部署测试代币失败：%s", err)
+	}
+	if rp := c.Mine(t, tx); rp.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("部署测试代币失败")
+	}
+	return addr, token
+}
+
+// Mint 给 to 增发 amount 个代币（最小单位）
+func (c *Chain) Mint(t testing.TB, token *TestToken, to common.Address, amount int64) {
+	t.Helper()
+	tx, err := token.Mint(c.Transactor(t, 0), to, big.NewInt(amount))
+	if err != nil {
+		t.Fatalf("增发测试代币失败：%s", err)
+	}
+	if rp := c.Mine(t, tx); rp.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("增发测试代币失败")
+	}
+}
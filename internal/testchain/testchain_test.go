@@ -0,0 +1,88 @@
+package testchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestTestToken(t *testing.T) {
+	for _, quiet := range []bool{false, true} {
+		c := New(t, 3)
+		tokenAddr, token := c.DeployToken(t, 0, quiet)
+		owner, spender, to := c.Address(0), c.Address(1), c.Address(2)
+		c.Mint(t, token, owner, 1000)
+
+		balance := func(addr common.Address) int64 {
+			v, err := token.BalanceOf(&bind.CallOpts{}, addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return v.Int64()
+		}
+		send := func(i int, fn func(opts *bind.TransactOpts) (*types.Transaction, error)) uint64 {
+			opts := c.Transactor(t, i)
+			opts.GasLimit = 200000
+			tx, err := fn(opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return c.Mine(t, tx).Status
+		}
+
+		if s := send(0, func(o *bind.TransactOpts) (*types.Transaction, error) { return token.Transfer(o, to, big.NewInt(300)) }); s != types.ReceiptStatusSuccessful {
+			t.Fatalf("quiet=%v：转账失败", quiet)
+		}
+		// quiet 时 transfer 不返回数据
+		parsed, _ := TestTokenMetaData.GetAbi()
+		data, _ := parsed.Pack("transfer", to, big.NewInt(1))
+		out, err := c.CallContract(context.Background(), ethereum.CallMsg{From: owner, To: &tokenAddr, Data: data}, nil)
+		if err != nil || (len(out) == 0) != quiet {
+			t.Fatalf("quiet=%v：transfer 返回 %x, %v", quiet, out, err)
+		}
+		if balance(owner) != 700 || balance(to) != 300 {
+			t.Fatalf("quiet=%v：转账后余额 %d、%d，预期 700、300", quiet, balance(owner), balance(to))
+		}
+		// 余额不足、转给 0 地址、没有授权时回滚
+		for name, fn := range map[string]func(o *bind.TransactOpts) (*types.Transaction, error){
+			"余额不足": func(o *bind.TransactOpts) (*types.Transaction, error) { return token.Transfer(o, to, big.NewInt(701)) },
+			"转给 0 地址": func(o *bind.TransactOpts) (*types.Transaction, error) {
+				return token.Transfer(o, common.Address{}, big.NewInt(1))
+			},
+		} {
+			if s := send(0, fn); s != types.ReceiptStatusFailed {
+				t.Errorf("quiet=%v：%s时应当回滚", quiet, name)
+			}
+		}
+		if s := send(1, func(o *bind.TransactOpts) (*types.Transaction, error) {
+			return token.TransferFrom(o, owner, to, big.NewInt(1))
+		}); s != types.ReceiptStatusFailed {
+			t.Errorf("quiet=%v：没有授权时 transferFrom 应当回滚", quiet)
+		}
+
+		send(0, func(o *bind.TransactOpts) (*types.Transaction, error) {
+			return token.Approve(o, spender, big.NewInt(200))
+		})
+		if v, _ := token.Allowance(&bind.CallOpts{}, owner, spender); v.Int64() != 200 {
+			t.Fatalf("quiet=%v：授权额度 %s，预期 200", quiet, v)
+		}
+		if s := send(1, func(o *bind.TransactOpts) (*types.Transaction, error) {
+			return token.TransferFrom(o, owner, to, big.NewInt(150))
+		}); s != types.ReceiptStatusSuccessful {
+			t.Fatalf("quiet=%v：transferFrom 失败", quiet)
+		}
+		if v, _ := token.Allowance(&bind.CallOpts{}, owner, spender); v.Int64() != 50 || balance(to) != 450 {
+			t.Fatalf("quiet=%v：transferFrom 后授权 %s、余额 %d，预期 50、450", quiet, v, balance(to))
+		}
+		if s := send(1, func(o *bind.TransactOpts) (*types.Transaction, error) {
+			return token.TransferFrom(o, owner, to, big.NewInt(51))
+		}); s != types.ReceiptStatusFailed {
+			t.Errorf("quiet=%v：超过授权额度时 transferFrom 应当回滚", quiet)
+		}
+	}
+}
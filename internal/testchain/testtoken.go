@@ -0,0 +1,379 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package testchain
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
+
+// TestTokenMetaData contains all meta data concerning the TestToken contract.
+var TestTokenMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"name\":\"quiet\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"inputs\":[{\"name\":\"to\",\"type\":\"address\"},{\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"from\",\"type\":\"address\"},{\"name\":\"to\",\"type\":\"address\"},{\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"spender\",\"type\":\"address\"},{\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"owner\",\"type\":\"address\"},{\"name\":\"spender\",\"type\":\"address\"}],\"name\":\"allowance\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"who\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"decimals\",\"outputs\":[{\"name\":\"\",\"type\":\"uint8\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"to\",\"type\":\"address\"},{\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"mint\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+	Bin: "0x610020803803600039610000516100005561017f8061001e6000396000f3346300000085576000357c010000000000000000000000000000000000000000000000000000000090048063a9059cbb14630000008a57806323b872dd146300000098578063095ea7b31463000000ff578063dd62ed3e14630000012f57806370a0823114630000014a578063313ce56714630000015f57806340c10f1914630000016a575b600080fd5b6024356004353363000000c9565b60043560005233602052604060002080546044358082106300000085579003905560443560243560043563000000c9565b81156300000085576000526020600020805483811063000000855783900390556000526020600020805482019055506300000119565b336000526004356020526024356040600020556300000119565b600054630000012d57600160005260206000f35b005b60043560005260243560205260406000205460005260206000f35b60043560005260206000205460005260206000f35b600260005260206000f35b6004356000526020600020805460243501905500",
+}
+
+// TestTokenABI is the input ABI used to generate the binding from.
+// Deprecated: Use TestTokenMetaData.ABI instead.
+var TestTokenABI = TestTokenMetaData.ABI
+
+// TestTokenBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use TestTokenMetaData.Bin instead.
+var TestTokenBin = TestTokenMetaData.Bin
+
+// DeployTestToken deploys a new Ethereum contract, binding an instance of TestToken to it.
+func DeployTestToken(auth *bind.TransactOpts, backend bind.ContractBackend, quiet bool) (common.Address, *types.Transaction, *TestToken, error) {
+	parsed, err := TestTokenMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(TestTokenBin), backend, quiet)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &TestToken{TestTokenCaller: TestTokenCaller{contract: contract}, TestTokenTransactor: TestTokenTransactor{contract: contract}, TestTokenFilterer: TestTokenFilterer{contract: contract}}, nil
+}
+
+// TestToken is an auto generated Go binding around an Ethereum contract.
+type TestToken struct {
+	TestTokenCaller     // Read-only binding to the contract
+	TestTokenTransactor // Write-only binding to the contract
+	TestTokenFilterer   // Log filterer for contract events
+}
+
+// TestTokenCaller is an auto generated read-only Go binding around an Ethereum contract.
+type TestTokenCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// TestTokenTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type TestTokenTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// TestTokenFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type TestTokenFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// TestTokenSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type TestTokenSession struct {
+	Contract     *TestToken        // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// TestTokenCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type TestTokenCallerSession struct {
+	Contract *TestTokenCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts    // Call options to use throughout this session
+}
+
+// TestTokenTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type TestTokenTransactorSession struct {
+	Contract     *TestTokenTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts    // Transaction auth options to use throughout this session
+}
+
+// TestTokenRaw is an auto generated low-level Go binding around an Ethereum contract.
+type TestTokenRaw struct {
+	Contract *TestToken // Generic contract binding to access the raw methods on
+}
+
+// TestTokenCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type TestTokenCallerRaw struct {
+	Contract *TestTokenCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// TestTokenTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type TestTokenTransactorRaw struct {
+	Contract *TestTokenTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewTestToken creates a new instance of TestToken, bound to a specific deployed contract.
+func NewTestToken(address common.Address, backend bind.ContractBackend) (*TestToken, error) {
+	contract, err := bindTestToken(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &TestToken{TestTokenCaller: TestTokenCaller{contract: contract}, TestTokenTransactor: TestTokenTransactor{contract: contract}, TestTokenFilterer: TestTokenFilterer{contract: contract}}, nil
+}
+
+// NewTestTokenCaller creates a new read-only instance of TestToken, bound to a specific deployed contract.
+func NewTestTokenCaller(address common.Address, caller bind.ContractCaller) (*TestTokenCaller, error) {
+	contract, err := bindTestToken(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TestTokenCaller{contract: contract}, nil
+}
+
+// NewTestTokenTransactor creates a new write-only instance of TestToken, bound to a specific deployed contract.
+func NewTestTokenTransactor(address common.Address, transactor bind.ContractTransactor) (*TestTokenTransactor, error) {
+	contract, err := bindTestToken(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TestTokenTransactor{contract: contract}, nil
+}
+
+// NewTestTokenFilterer creates a new log filterer instance of TestToken, bound to a specific deployed contract.
+func NewTestTokenFilterer(address common.Address, filterer bind.ContractFilterer) (*TestTokenFilterer, error) {
+	contract, err := bindTestToken(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &TestTokenFilterer{contract: contract}, nil
+}
+
+// bindTestToken binds a generic wrapper to an already deployed contract.
+func bindTestToken(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(TestTokenABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_TestToken *TestTokenRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _TestToken.Contract.TestTokenCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_TestToken *TestTokenRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _TestToken.Contract.TestTokenTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_TestToken *TestTokenRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _TestToken.Contract.TestTokenTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_TestToken *TestTokenCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _TestToken.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_TestToken *TestTokenTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _TestToken.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_TestToken *TestTokenTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _TestToken.Contract.contract.Transact(opts, method, params...)
+}
+
+// Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
+//
+// Solidity: function allowance(address owner, address spender) view returns(uint256)
+func (_TestToken *TestTokenCaller) Allowance(opts *bind.CallOpts, owner common.Address, spender common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _TestToken.contract.Call(opts, &out, "allowance", owner, spender)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
+//
+// Solidity: function allowance(address owner, address spender) view returns(uint256)
+func (_TestToken *TestTokenSession) Allowance(owner common.Address, spender common.Address) (*big.Int, error) {
+	return _TestToken.Contract.Allowance(&_TestToken.CallOpts, owner, spender)
+}
+
+// Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
+//
+// Solidity: function allowance(address owner, address spender) view returns(uint256)
+func (_TestToken *TestTokenCallerSession) Allowance(owner common.Address, spender common.Address) (*big.Int, error) {
+	return _TestToken.Contract.Allowance(&_TestToken.CallOpts, owner, spender)
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf(address who) view returns(uint256)
+func (_TestToken *TestTokenCaller) BalanceOf(opts *bind.CallOpts, who common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _TestToken.contract.Call(opts, &out, "balanceOf", who)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf(address who) view returns(uint256)
+func (_TestToken *TestTokenSession) BalanceOf(who common.Address) (*big.Int, error) {
+	return _TestToken.Contract.BalanceOf(&_TestToken.CallOpts, who)
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf(address who) view returns(uint256)
+func (_TestToken *TestTokenCallerSession) BalanceOf(who common.Address) (*big.Int, error) {
+	return _TestToken.Contract.BalanceOf(&_TestToken.CallOpts, who)
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() view returns(uint8)
+func (_TestToken *TestTokenCaller) Decimals(opts *bind.CallOpts) (uint8, error) {
+	var out []interface{}
+	err := _TestToken.contract.Call(opts, &out, "decimals")
+
+	if err != nil {
+		return *new(uint8), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(uint8)).(*uint8)
+
+	return out0, err
+
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() view returns(uint8)
+func (_TestToken *TestTokenSession) Decimals() (uint8, error) {
+	return _TestToken.Contract.Decimals(&_TestToken.CallOpts)
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() view returns(uint8)
+func (_TestToken *TestTokenCallerSession) Decimals() (uint8, error) {
+	return _TestToken.Contract.Decimals(&_TestToken.CallOpts)
+}
+
+// Approve is a paid mutator transaction binding the contract method 0x095ea7b3.
+//
+// Solidity: function approve(address spender, uint256 value) returns(bool)
+func (_TestToken *TestTokenTransactor) Approve(opts *bind.TransactOpts, spender common.Address, value *big.Int) (*types.Transaction, error) {
+	return _TestToken.contract.Transact(opts, "approve", spender, value)
+}
+
+// Approve is a paid mutator transaction binding the contract method 0x095ea7b3.
+//
+// Solidity: function approve(address spender, uint256 value) returns(bool)
+func (_TestToken *TestTokenSession) Approve(spender common.Address, value *big.Int) (*types.Transaction, error) {
+	return _TestToken.Contract.Approve(&_TestToken.TransactOpts, spender, value)
+}
+
+// Approve is a paid mutator transaction binding the contract method 0x095ea7b3.
+//
+// Solidity: function approve(address spender, uint256 value) returns(bool)
+func (_TestToken *TestTokenTransactorSession) Approve(spender common.Address, value *big.Int) (*types.Transaction, error) {
+	return _TestToken.Contract.Approve(&_TestToken.TransactOpts, spender, value)
+}
+
+// Mint is a paid mutator transaction binding the contract method 0x40c10f19.
+//
+// Solidity: function mint(address to, uint256 value) returns()
+func (_TestToken *TestTokenTransactor) Mint(opts *bind.TransactOpts, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _TestToken.contract.Transact(opts, "mint", to, value)
+}
+
+// Mint is a paid mutator transaction binding the contract method 0x40c10f19.
+//
+// Solidity: function mint(address to, uint256 value) returns()
+func (_TestToken *TestTokenSession) Mint(to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _TestToken.Contract.Mint(&_TestToken.TransactOpts, to, value)
+}
+
+// Mint is a paid mutator transaction binding the contract method 0x40c10f19.
+//
+// Solidity: function mint(address to, uint256 value) returns()
+func (_TestToken *TestTokenTransactorSession) Mint(to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _TestToken.Contract.Mint(&_TestToken.TransactOpts, to, value)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(address to, uint256 value) returns(bool)
+func (_TestToken *TestTokenTransactor) Transfer(opts *bind.TransactOpts, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _TestToken.contract.Transact(opts, "transfer", to, value)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(address to, uint256 value) returns(bool)
+func (_TestToken *TestTokenSession) Transfer(to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _TestToken.Contract.Transfer(&_TestToken.TransactOpts, to, value)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(address to, uint256 value) returns(bool)
+func (_TestToken *TestTokenTransactorSession) Transfer(to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _TestToken.Contract.Transfer(&_TestToken.TransactOpts, to, value)
+}
+
+// TransferFrom is a paid mutator transaction binding the contract method 0x23b872dd.
+//
+// Solidity: function transferFrom(address from, address to, uint256 value) returns(bool)
+func (_TestToken *TestTokenTransactor) TransferFrom(opts *bind.TransactOpts, from common.Address, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _TestToken.contract.Transact(opts, "transferFrom", from, to, value)
+}
+
+// TransferFrom is a paid mutator transaction binding the contract method 0x23b872dd.
+//
+// Solidity: function transferFrom(address from, address to, uint256 value) returns(bool)
+func (_TestToken *TestTokenSession) TransferFrom(from common.Address, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _TestToken.Contract.TransferFrom(&_TestToken.TransactOpts, from, to, value)
+}
+
+// TransferFrom is a paid mutator transaction binding the contract method 0x23b872dd.
+//
+// Solidity: function transferFrom(address from, address to, uint256 value) returns(bool)
+func (_TestToken *TestTokenTransactorSession) TransferFrom(from common.Address, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _TestToken.Contract.TransferFrom(&_TestToken.TransactOpts, from, to, value)
+}
@@ -0,0 +1,649 @@
+package uiutil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andlabs/ui"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/naiba/eth-tools/internal/keys"
+	"github.com/naiba/eth-tools/internal/netconf"
+	"github.com/naiba/eth-tools/internal/units"
+)
+
+// KeyPicker 选择签名密钥来源的输入框组，私钥与密码都不会以明文显示
+type KeyPicker struct {
+	Box *ui.Box
+
+	win      *ui.Window
+	kind     *ui.Combobox
+	location *ui.Entry
+	account  *ui.Entry
+	pass     *ui.Entry
+	addr     *ui.Label
+	hdRow    *ui.Box
+	mnemonic *ui.Entry
+	network  *NetworkPicker
+}
+
+// NewKeyPicker 生成密钥来源选择框，def 为默认的来源
+func NewKeyPicker(win *ui.Window, def keys.Spec) *KeyPicker {
+	k := &KeyPicker{win: win}
+	k.kind = ui.NewCombobox()
+	for _, name := range keys.KindNames {
+		k.kind.Append(name)
+	}
+	k.kind.SetSelected(0)
+	for i, kind := range keys.Kinds {
+		if kind == def.Kind {
+			k.kind.SetSelected(i)
+		}
+	}
+	k.kind.OnSelected(func(*ui.Combobox) {
+		k.showKeystore()
+		k.showHD()
+	})
+	k.location = ui.NewEntry()
+	k.location.SetText(def.Location)
+	fileBtn := ui.NewButton("选择文件")
+	fileBtn.OnClicked(func(*ui.Button) {
+		if path := ui.OpenFile(win); path != "" {
+			k.location.SetText(path)
+			k.showKeystore()
+		}
+	})
+	k.account = ui.NewEntry()
+	k.account.SetText(def.Account)
+	k.pass = ui.NewPasswordEntry()
+	vaultBtn := ui.NewButton("密钥保管库")
+	vaultBtn.OnClicked(func(*ui.Button) {
+		k.editVault()
+	})
+	keystoreBtn := ui.NewButton("导入/导出 Keystore")
+	keystoreBtn.OnClicked(func(*ui.Button) {
+		k.editKeystore()
+	})
+	k.addr = ui.NewLabel("")
+	k.mnemonic = ui.NewPasswordEntry()
+	listBtn := ui.NewButton("列出地址")
+	listBtn.OnClicked(func(*ui.Button) {
+		k.listAccounts()
+	})
+	newBtn := ui.NewButton("生成助记词")
+	newBtn.OnClicked(func(*ui.Button) {
+		k.newMnemonic()
+	})
+
+	row1 := ui.NewHorizontalBox()
+	row1.SetPadded(true)
+	row1.Append(ui.NewLabel("签名密钥"), false)
+	row1.Append(k.kind, false)
+	row1.Append(k.location, true)
+	row1.Append(fileBtn, false)
+	row2 := ui.NewHorizontalBox()
+	row2.SetPadded(true)
+	row2.Append(ui.NewLabel("账户/名称"), false)
+	row2.Append(k.account, true)
+	row2.Append(ui.NewLabel("密码"), false)
+	row2.Append(k.pass, true)
+	row2.Append(vaultBtn, false)
+	row2.Append(keystoreBtn, false)
+	k.Box = ui.NewVerticalBox()
+	k.Box.SetPadded(true)
+	k.Box.Append(row1, false)
+	k.Box.Append(row2, false)
+	k.hdRow = ui.NewHorizontalBox()
+	k.hdRow.SetPadded(true)
+	k.hdRow.Append(ui.NewLabel("助记词"), false)
+	k.hdRow.Append(k.mnemonic, true)
+	k.hdRow.Append(listBtn, false)
+	k.hdRow.Append(newBtn, false)
+	k.Box.Append(k.hdRow, false)
+	k.Box.Append(k.addr, false)
+	k.showKeystore()
+	k.showHD()
+	return k
+}
+
+// SetNetwork 列出助记词地址时用选中的网络查询余额
+func (k *KeyPicker) SetNetwork(p *NetworkPicker) {
+	k.network = p
+}
+
+// showHD 选择助记词时显示助记词输入框，派生路径填在位置框，账户序号填在账户框
+func (k *KeyPicker) showHD() {
+	if k.Spec().Kind != keys.KindMnemonic {
+		k.hdRow.Hide()
+		return
+	}
+	if k.location.Text() == "" {
+		k.location.SetText(keys.DefaultHDBase + "/n")
+	}
+	if k.account.Text() == "" {
+		k.account.SetText("0")
+	}
+	k.hdRow.Show()
+}
+
+// showKeystore 选择 keystore 文件后显示其中的地址，提示输入密码
+func (k *KeyPicker) showKeystore() {
+	spec := k.Spec()
+	if spec.Kind != keys.KindKeystore || spec.Location == "" {
+		k.addr.SetText("")
+		return
+	}
+	addr, err := keys.KeystoreAddress(spec.Location)
+	if err != nil {
+		k.addr.SetText(err.Error())
+		return
+	}
+	k.addr.SetText("Keystore 地址：" + addr.Hex() + "，请输入密码")
+}
+
+// use 切换到指定的密钥来源，密码需要重新输入
+func (k *KeyPicker) use(spec keys.Spec) {
+	for i, kind := range keys.Kinds {
+		if kind == spec.Kind {
+			k.kind.SetSelected(i)
+		}
+	}
+	k.location.SetText(spec.Location)
+	k.account.SetText(spec.Account)
+	k.pass.SetText("")
+	k.showKeystore()
+}
+
+// Spec 当前填写的密钥来源
+func (k *KeyPicker) Spec() keys.Spec {
+	spec := keys.Spec{
+		Kind:     keys.KindKeystore,
+		Location: strings.TrimSpace(k.location.Text()),
+		Account:  strings.TrimSpace(k.account.Text()),
+	}
+	if i := k.kind.Selected(); i >= 0 {
+		spec.Kind = keys.Kinds[i]
+	}
+	return spec
+}
+
+// Secret 填写的密码与助记词
+func (k *KeyPicker) Secret() keys.Secret {
+	return keys.Secret{Passphrase: k.pass.Text(), Mnemonic: k.mnemonic.Text()}
+}
+
+// newMnemonic 生成新的助记词。助记词不会直接显示：可以把当前路径与序号派生的账户加密保存到
+// 保管库或 keystore 文件，或者确认周围没有他人后再显示抄写；程序不会保存助记词
+func (k *KeyPicker) newMnemonic() {
+	mnemonic, err := keys.GenerateMnemonic()
+	if err != nil {
+		ui.MsgBoxError(k.win, "生成助记词失败", err.Error())
+		return
+	}
+	win := ui.NewWindow("新助记词", 420, 320, false)
+	win.SetMargined(true)
+	win.OnClosing(func(*ui.Window) bool {
+		return true
+	})
+	// hdSpec 当前选择的是助记词时沿用填写的派生路径与序号，否则使用默认路径的第 0 个账户
+	hdSpec := func() keys.Spec {
+		if spec := k.Spec(); spec.Kind == keys.KindMnemonic {
+			return spec
+		}
+		return keys.Spec{Kind: keys.KindMnemonic}
+	}
+	derive := func() (*ecdsa.PrivateKey, error) {
+		return keys.PrivateKey(hdSpec(), keys.Secret{Mnemonic: mnemonic})
+	}
+
+	form := ui.NewForm()
+	form.SetPadded(true)
+	nameEntry := ui.NewEntry()
+	passEntry := ui.NewPasswordEntry()
+	confirmEntry := ui.NewPasswordEntry()
+	form.Append("保管库中的名称", nameEntry, false)
+	form.Append("密码", passEntry, false)
+	form.Append("确认密码", confirmEntry, false)
+	// readKey 检查密码并派生私钥，出错时提示
+	readKey := func(title string) *ecdsa.PrivateKey {
+		if passEntry.Text() == "" || passEntry.Text() != confirmEntry.Text() {
+			ui.MsgBoxError(win, title, "密码不能为空，两次输入的密码需要一致")
+			return nil
+		}
+		pk, err := derive()
+		if err != nil {
+			ui.MsgBoxError(win, title, err.Error())
+			return nil
+		}
+		return pk
+	}
+	vaultBtn := ui.NewButton("加入保管库")
+	vaultBtn.OnClicked(func(*ui.Button) {
+		pk := readKey("加入失败")
+		if pk == nil {
+			return
+		}
+		name := strings.TrimSpace(nameEntry.Text())
+		v, err := keys.OpenVault("")
+		if err == nil {
+			err = v.Add(name, pk, passEntry.Text())
+		}
+		if err == nil {
+			err = v.Save()
+		}
+		if err != nil {
+			ui.MsgBoxError(win, "加入失败", err.Error())
+			return
+		}
+		passEntry.SetText("")
+		confirmEntry.SetText("")
+		k.use(keys.Spec{Kind: keys.KindVault, Account: name})
+		ui.MsgBox(win, "已加入保管库", crypto.PubkeyToAddress(pk.PublicKey).Hex()+" 已保存为 "+name)
+	})
+	keystoreBtn := ui.NewButton("导出 Keystore")
+	keystoreBtn.OnClicked(func(*ui.Button) {
+		pk := readKey("导出失败")
+		if pk == nil {
+			return
+		}
+		path := ui.SaveFile(win)
+		if path == "" {
+			return
+		}
+		path, err := keys.ExportKeystore(pk, passEntry.Text(), path)
+		if err != nil {
+			ui.MsgBoxError(win, "导出失败", err.Error())
+			return
+		}
+		passEntry.SetText("")
+		confirmEntry.SetText("")
+		k.use(keys.Spec{Kind: keys.KindKeystore, Location: path})
+		ui.MsgBox(win, "导出成功", crypto.PubkeyToAddress(pk.PublicKey).Hex()+" 已导出到 "+path)
+	})
+	saveBtns := ui.NewHorizontalBox()
+	saveBtns.SetPadded(true)
+	saveBtns.Append(vaultBtn, true)
+	saveBtns.Append(keystoreBtn, true)
+	saveBox := ui.NewVerticalBox()
+	saveBox.SetPadded(true)
+	saveBox.Append(form, false)
+	saveBox.Append(saveBtns, false)
+	saveGroup := ui.NewGroup("加密保存派生的账户")
+	saveGroup.SetMargined(true)
+	saveGroup.SetChild(saveBox)
+
+	words := ui.NewMultilineEntry()
+	words.SetReadOnly(true)
+	confirmCheck := ui.NewCheckbox("周围没有他人，也没有录屏或截屏")
+	showBtn := ui.NewButton("显示助记词")
+	showBtn.Disable()
+	confirmCheck.OnToggled(func(c *ui.Checkbox) {
+		if c.Checked() {
+			showBtn.Enable()
+			return
+		}
+		showBtn.Disable()
+		words.SetText("")
+	})
+	showBtn.OnClicked(func(*ui.Button) {
+		words.SetText(mnemonic + "\n\n请抄写在纸上妥善保存，关闭窗口后不会再显示。")
+	})
+	useBtn := ui.NewButton("填入助记词输入框")
+	useBtn.OnClicked(func(*ui.Button) {
+		k.use(hdSpec())
+		k.mnemonic.SetText(mnemonic)
+		k.showHD()
+		win.Destroy()
+	})
+	showBtns := ui.NewHorizontalBox()
+	showBtns.SetPadded(true)
+	showBtns.Append(confirmCheck, true)
+	showBtns.Append(showBtn, false)
+	showBtns.Append(useBtn, false)
+	showBox := ui.NewVerticalBox()
+	showBox.SetPadded(true)
+	showBox.Append(showBtns, false)
+	showBox.Append(words, true)
+	showGroup := ui.NewGroup("抄写助记词")
+	showGroup.SetMargined(true)
+	showGroup.SetChild(showBox)
+
+	box := ui.NewVerticalBox()
+	box.SetPadded(true)
+	box.Append(ui.NewLabel("已生成新助记词，程序不会保存它。只加密保存账户时，关闭窗口后助记词无法找回。"), false)
+	box.Append(saveGroup, false)
+	box.Append(showGroup, true)
+	win.SetChild(box)
+	win.Show()
+}
+
+// listAccounts 列出助记词派生的前几个地址及余额，可以选择其中一个用于签名
+func (k *KeyPicker) listAccounts() {
+	spec, secret := k.Spec(), k.Secret()
+	w, err := keys.NewHDWallet(secret.Mnemonic, secret.Passphrase)
+	if err != nil {
+		ui.MsgBoxError(k.win, "无法列出地址", err.Error())
+		return
+	}
+	win := ui.NewWindow("助记词地址", 520, 360, false)
+	win.SetMargined(true)
+	win.OnClosing(func(*ui.Window) bool {
+		return true
+	})
+	list := ui.NewMultilineEntry()
+	list.SetReadOnly(true)
+	countEntry := ui.NewEntry()
+	countEntry.SetText("10")
+	indexEntry := ui.NewEntry()
+	indexEntry.SetText(k.account.Text())
+
+	show := func() {
+		count, err := strconv.Atoi(strings.TrimSpace(countEntry.Text()))
+		if err != nil || count <= 0 || count > 100 {
+			ui.MsgBoxError(win, "数量有误", "请填写 1 到 100 之间的数量")
+			return
+		}
+		addrs, err := w.Addresses(spec.Location, count)
+		if err != nil {
+			ui.MsgBoxError(win, "无法列出地址", err.Error())
+			return
+		}
+		lines := make([]string, len(addrs))
+		for i, addr := range addrs {
+			lines[i] = fmt.Sprintf("%d：%s", i, addr.Hex())
+		}
+		list.SetText(strings.Join(lines, "\n"))
+		if k.network == nil {
+			return
+		}
+		network := k.network.Selected()
+		go func() {
+			balances, err := nativeBalances(network, addrs)
+			ui.QueueMain(func() {
+				if err != nil {
+					list.Append("\n查询余额失败：" + err.Error())
+					return
+				}
+				for i := range lines {
+					lines[i] += "  " + balances[i] + " " + network.Symbol
+				}
+				list.SetText(strings.Join(lines, "\n"))
+			})
+		}()
+	}
+	showBtn := ui.NewButton("刷新")
+	showBtn.OnClicked(func(*ui.Button) {
+		show()
+	})
+	useBtn := ui.NewButton("使用该账户")
+	useBtn.OnClicked(func(*ui.Button) {
+		index, err := strconv.ParseUint(strings.TrimSpace(indexEntry.Text()), 10, 31)
+		if err != nil {
+			ui.MsgBoxError(win, "序号有误", indexEntry.Text())
+			return
+		}
+		k.account.SetText(strconv.FormatUint(index, 10))
+		win.Destroy()
+	})
+
+	ctrlBox := ui.NewHorizontalBox()
+	ctrlBox.SetPadded(true)
+	ctrlBox.Append(ui.NewLabel("数量"), false)
+	ctrlBox.Append(countEntry, false)
+	ctrlBox.Append(showBtn, false)
+	ctrlBox.Append(ui.NewLabel("账户序号"), false)
+	ctrlBox.Append(indexEntry, false)
+	ctrlBox.Append(useBtn, true)
+	box := ui.NewVerticalBox()
+	box.SetPadded(true)
+	box.Append(list, true)
+	box.Append(ctrlBox, false)
+	win.SetChild(box)
+	win.Show()
+	show()
+}
+
+// nativeBalances 批量查询一组地址的原生币余额
+func nativeBalances(network netconf.Profile, addrs []common.Address) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	rc, _, err := network.Dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	results := make([]hexutil.Big, len(addrs))
+	elems := make([]rpc.BatchElem, len(addrs))
+	for i, addr := range addrs {
+		elems[i] = rpc.BatchElem{Method: "eth_getBalance", Args: []interface{}{addr, "latest"}, Result: &results[i]}
+	}
+	if err := rc.BatchCallContext(ctx, elems); err != nil {
+		return nil, err
+	}
+	balances := make([]string, len(addrs))
+	for i := range elems {
+		if elems[i].Error != nil {
+			balances[i] = "查询失败"
+			continue
+		}
+		balances[i] = units.FormatEther(results[i].ToInt())
+	}
+	return balances, nil
+}
+
+// editKeystore 打开 keystore 窗口：导入已有的 keystore 文件，
+// 或把十六进制私钥（留空则新生成）用密码加密导出为 keystore 文件
+func (k *KeyPicker) editKeystore() {
+	win := ui.NewWindow("Keystore", 420, 300, false)
+	win.SetMargined(true)
+	win.OnClosing(func(*ui.Window) bool {
+		return true
+	})
+
+	importForm := ui.NewForm()
+	importForm.SetPadded(true)
+	srcLabel := ui.NewLabel("")
+	srcPath := ""
+	srcBtn := ui.NewButton("选择 keystore 文件")
+	srcBtn.OnClicked(func(*ui.Button) {
+		path := ui.OpenFile(win)
+		if path == "" {
+			return
+		}
+		addr, err := keys.KeystoreAddress(path)
+		if err != nil {
+			ui.MsgBoxError(win, "无法导入", err.Error())
+			return
+		}
+		srcPath = path
+		srcLabel.SetText(addr.Hex())
+	})
+	importPass := ui.NewPasswordEntry()
+	importForm.Append("文件", srcBtn, false)
+	importForm.Append("地址", srcLabel, false)
+	importForm.Append("密码", importPass, false)
+	importBtn := ui.NewButton("验证密码并导入")
+	importBtn.OnClicked(func(*ui.Button) {
+		if srcPath == "" {
+			ui.MsgBoxError(win, "导入失败", "请先选择 keystore 文件")
+			return
+		}
+		dst, addr, err := keys.ImportKeystore(srcPath, importPass.Text())
+		if err != nil {
+			ui.MsgBoxError(win, "导入失败", err.Error())
+			return
+		}
+		importPass.SetText("")
+		k.use(keys.Spec{Kind: keys.KindKeystore, Location: dst})
+		ui.MsgBox(win, "导入成功", addr.Hex()+" 已导入到 "+dst)
+	})
+	importBox := ui.NewVerticalBox()
+	importBox.SetPadded(true)
+	importBox.Append(importForm, false)
+	importBox.Append(importBtn, false)
+	importGroup := ui.NewGroup("导入")
+	importGroup.SetMargined(true)
+	importGroup.SetChild(importBox)
+
+	exportForm := ui.NewForm()
+	exportForm.SetPadded(true)
+	keyEntry := ui.NewPasswordEntry()
+	passEntry := ui.NewPasswordEntry()
+	confirmEntry := ui.NewPasswordEntry()
+	exportForm.Append("私钥（十六进制，留空则新生成）", keyEntry, false)
+	exportForm.Append("密码", passEntry, false)
+	exportForm.Append("确认密码", confirmEntry, false)
+	exportBtn := ui.NewButton("导出")
+	exportBtn.OnClicked(func(*ui.Button) {
+		if passEntry.Text() != confirmEntry.Text() {
+			ui.MsgBoxError(win, "导出失败", "两次输入的密码不一致")
+			return
+		}
+		var pk *ecdsa.PrivateKey
+		var err error
+		if hex := strings.TrimSpace(keyEntry.Text()); hex != "" {
+			if pk, err = keys.ParseHexKey(hex); err != nil {
+				ui.MsgBoxError(win, "导出失败", err.Error())
+				return
+			}
+		} else if pk, err = crypto.GenerateKey(); err != nil {
+			ui.MsgBoxError(win, "导出失败", err.Error())
+			return
+		}
+		path := ui.SaveFile(win)
+		if path == "" {
+			return
+		}
+		path, err = keys.ExportKeystore(pk, passEntry.Text(), path)
+		if err != nil {
+			ui.MsgBoxError(win, "导出失败", err.Error())
+			return
+		}
+		keyEntry.SetText("")
+		passEntry.SetText("")
+		confirmEntry.SetText("")
+		k.use(keys.Spec{Kind: keys.KindKeystore, Location: path})
+		ui.MsgBox(win, "导出成功", crypto.PubkeyToAddress(pk.PublicKey).Hex()+" 已导出到 "+path)
+	})
+	exportBox := ui.NewVerticalBox()
+	exportBox.SetPadded(true)
+	exportBox.Append(exportForm, false)
+	exportBox.Append(exportBtn, false)
+	exportGroup := ui.NewGroup("导出")
+	exportGroup.SetMargined(true)
+	exportGroup.SetChild(exportBox)
+
+	box := ui.NewVerticalBox()
+	box.SetPadded(true)
+	box.Append(importGroup, false)
+	box.Append(exportGroup, false)
+	win.SetChild(box)
+	win.Show()
+}
+
+// editVault 打开密钥保管库窗口，可以加入或删除密钥，私钥只能写入不能查看
+func (k *KeyPicker) editVault() {
+	spec := k.Spec()
+	path := ""
+	if spec.Kind == keys.KindVault {
+		path = spec.Location
+	}
+	v, err := keys.OpenVault(path)
+	if err != nil {
+		ui.MsgBoxError(k.win, "打开密钥保管库失败", err.Error())
+		return
+	}
+	win := ui.NewWindow("密钥保管库", 420, 300, false)
+	win.SetMargined(true)
+	win.OnClosing(func(*ui.Window) bool {
+		return true
+	})
+
+	list := ui.NewMultilineEntry()
+	list.SetReadOnly(true)
+	showList := func() {
+		var lines []string
+		for _, name := range v.Names() {
+			addr, err := v.Address(name)
+			if err != nil {
+				lines = append(lines, name+"：无法读取地址")
+				continue
+			}
+			lines = append(lines, name+"："+addr.Hex())
+		}
+		list.SetText(strings.Join(lines, "\n"))
+	}
+	showList()
+
+	form := ui.NewForm()
+	form.SetPadded(true)
+	nameEntry := ui.NewEntry()
+	keyEntry := ui.NewPasswordEntry()
+	passEntry := ui.NewPasswordEntry()
+	confirmEntry := ui.NewPasswordEntry()
+	form.Append("名称", nameEntry, false)
+	form.Append("私钥（十六进制，留空则新生成）", keyEntry, false)
+	form.Append("密码", passEntry, false)
+	form.Append("确认密码", confirmEntry, false)
+
+	addBtn := ui.NewButton("加入保管库")
+	addBtn.OnClicked(func(*ui.Button) {
+		if passEntry.Text() != confirmEntry.Text() {
+			ui.MsgBoxError(win, "加入失败", "两次输入的密码不一致")
+			return
+		}
+		var pk *ecdsa.PrivateKey
+		var err error
+		if hex := strings.TrimSpace(keyEntry.Text()); hex != "" {
+			if pk, err = keys.ParseHexKey(hex); err != nil {
+				ui.MsgBoxError(win, "加入失败", err.Error())
+				return
+			}
+		} else if pk, err = crypto.GenerateKey(); err != nil {
+			ui.MsgBoxError(win, "加入失败", err.Error())
+			return
+		}
+		if err := v.Add(nameEntry.Text(), pk, passEntry.Text()); err != nil {
+			ui.MsgBoxError(win, "加入失败", err.Error())
+			return
+		}
+		if err := v.Save(); err != nil {
+			ui.MsgBoxError(win, "加入失败", err.Error())
+			return
+		}
+		keyEntry.SetText("")
+		passEntry.SetText("")
+		confirmEntry.SetText("")
+		showList()
+	})
+	removeBtn := ui.NewButton("删除")
+	removeBtn.OnClicked(func(*ui.Button) {
+		if err := v.Remove(strings.TrimSpace(nameEntry.Text())); err != nil {
+			ui.MsgBoxError(win, "删除失败", err.Error())
+			return
+		}
+		if err := v.Save(); err != nil {
+			ui.MsgBoxError(win, "删除失败", err.Error())
+			return
+		}
+		showList()
+	})
+	btnBox := ui.NewHorizontalBox()
+	btnBox.SetPadded(true)
+	btnBox.Append(removeBtn, false)
+	btnBox.Append(addBtn, true)
+
+	box := ui.NewVerticalBox()
+	box.SetPadded(true)
+	box.Append(list, true)
+	box.Append(form, false)
+	box.Append(btnBox, false)
+	win.SetChild(box)
+	win.Show()
+}
@@ -0,0 +1,197 @@
+package uiutil
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/andlabs/ui"
+	"github.com/naiba/eth-tools/internal/ethutil"
+	"github.com/naiba/eth-tools/internal/netconf"
+)
+
+// NetworkPicker 选择网络的下拉框，旁边的按钮打开网络编辑窗口
+type NetworkPicker struct {
+	Box *ui.Box
+
+	cfg        *netconf.Config
+	comboBox   *ui.Box
+	combo      *ui.Combobox
+	selected   int
+	onSelected func(p netconf.Profile)
+}
+
+// NewNetworkPicker 生成网络选择框，默认选中第一个网络
+func NewNetworkPicker(cfg *netconf.Config) *NetworkPicker {
+	p := &NetworkPicker{cfg: cfg}
+	p.Box = ui.NewHorizontalBox()
+	p.Box.SetPadded(true)
+	p.comboBox = ui.NewHorizontalBox()
+	editBtn := ui.NewButton("编辑网络")
+	editBtn.OnClicked(func(*ui.Button) {
+		p.edit()
+	})
+	p.Box.Append(ui.NewLabel("选择网络"), false)
+	p.Box.Append(p.comboBox, true)
+	p.Box.Append(editBtn, false)
+	p.refresh("")
+	return p
+}
+
+// Selected 当前选中的网络
+func (p *NetworkPicker) Selected() netconf.Profile {
+	return p.cfg.Profiles[p.selected]
+}
+
+// Select 选中名为 name 的网络，找不到时不改变
+func (p *NetworkPicker) Select(name string) {
+	for i, profile := range p.cfg.Profiles {
+		if strings.EqualFold(profile.Name, name) {
+			p.selected = i
+			p.combo.SetSelected(i)
+			p.changed()
+			return
+		}
+	}
+}
+
+// OnSelected 切换网络（包括编辑后重新选中）时回调
+func (p *NetworkPicker) OnSelected(f func(p netconf.Profile)) {
+	p.onSelected = f
+}
+
+// refresh 按配置重建下拉框并选中名为 name 的网络，找不到时选中第一个
+func (p *NetworkPicker) refresh(name string) {
+	// 下拉框不支持清空，直接换一个新的
+	if p.combo != nil {
+		p.comboBox.Delete(0)
+	}
+	p.combo = ui.NewCombobox()
+	p.selected = 0
+	for i, profile := range p.cfg.Profiles {
+		p.combo.Append(profile.Name + " #" + strconv.FormatUint(profile.ChainID, 10))
+		if strings.EqualFold(profile.Name, name) {
+			p.selected = i
+		}
+	}
+	p.combo.SetSelected(p.selected)
+	p.combo.OnSelected(func(c *ui.Combobox) {
+		if c.Selected() < 0 {
+			return
+		}
+		p.selected = c.Selected()
+		p.changed()
+	})
+	p.comboBox.Append(p.combo, true)
+	p.changed()
+}
+
+func (p *NetworkPicker) changed() {
+	if p.onSelected != nil {
+		p.onSelected(p.Selected())
+	}
+}
+
+// edit 打开网络编辑窗口，默认编辑当前选中的网络，修改名称后保存为新网络
+func (p *NetworkPicker) edit() {
+	win := ui.NewWindow("编辑网络", 420, 260, false)
+	win.SetMargined(true)
+	win.OnClosing(func(*ui.Window) bool {
+		return true
+	})
+
+	form := ui.NewForm()
+	form.SetPadded(true)
+	nameEntry := ui.NewEntry()
+	rpcEntry := ui.NewEntry()
+	chainEntry := ui.NewEntry()
+	explorerEntry := ui.NewEntry()
+	symbolEntry := ui.NewEntry()
+	feeCombo := ui.NewCombobox()
+	for _, name := range ethutil.FeeModeNames {
+		feeCombo.Append(name)
+	}
+	form.Append("名称", nameEntry, false)
+	form.Append("节点地址（多个用逗号分隔）", rpcEntry, false)
+	form.Append("链 ID", chainEntry, false)
+	form.Append("浏览器链接模板（{tx} 为交易哈希）", explorerEntry, false)
+	form.Append("币种符号", symbolEntry, false)
+	form.Append("交易类型", feeCombo, false)
+
+	fill := func(profile netconf.Profile) {
+		nameEntry.SetText(profile.Name)
+		rpcEntry.SetText(strings.Join(profile.RPC, ","))
+		chainEntry.SetText("")
+		if profile.ChainID > 0 {
+			chainEntry.SetText(strconv.FormatUint(profile.ChainID, 10))
+		}
+		explorerEntry.SetText(profile.Explorer)
+		symbolEntry.SetText(profile.Symbol)
+		feeCombo.SetSelected(0)
+		for i, mode := range ethutil.FeeModes {
+			if mode == profile.Fee {
+				feeCombo.SetSelected(i)
+			}
+		}
+	}
+	fill(p.Selected())
+
+	newBtn := ui.NewButton("新建")
+	newBtn.OnClicked(func(*ui.Button) {
+		fill(netconf.Profile{Symbol: "ETH", Fee: ethutil.FeeAuto})
+	})
+	saveBtn := ui.NewButton("保存")
+	saveBtn.OnClicked(func(*ui.Button) {
+		chainID, err := strconv.ParseUint(strings.TrimSpace(chainEntry.Text()), 10, 64)
+		if err != nil {
+			ui.MsgBoxError(win, "保存失败", "链 ID 有误："+chainEntry.Text())
+			return
+		}
+		profile := netconf.Profile{
+			Name:     nameEntry.Text(),
+			RPC:      strings.Split(rpcEntry.Text(), ","),
+			ChainID:  chainID,
+			Explorer: explorerEntry.Text(),
+			Symbol:   symbolEntry.Text(),
+			Fee:      ethutil.FeeAuto,
+		}
+		if i := feeCombo.Selected(); i >= 0 {
+			profile.Fee = ethutil.FeeModes[i]
+		}
+		if err := p.cfg.Put(profile); err != nil {
+			ui.MsgBoxError(win, "保存失败", err.Error())
+			return
+		}
+		if err := p.cfg.Save(); err != nil {
+			ui.MsgBoxError(win, "保存失败", err.Error())
+			return
+		}
+		p.refresh(profile.Name)
+		ui.MsgBox(win, "保存成功", "网络配置已保存到 "+p.cfg.Path())
+	})
+	deleteBtn := ui.NewButton("删除")
+	deleteBtn.OnClicked(func(*ui.Button) {
+		name := nameEntry.Text()
+		if err := p.cfg.Delete(name); err != nil {
+			ui.MsgBoxError(win, "删除失败", err.Error())
+			return
+		}
+		if err := p.cfg.Save(); err != nil {
+			ui.MsgBoxError(win, "删除失败", err.Error())
+			return
+		}
+		p.refresh("")
+		fill(p.Selected())
+	})
+	btnBox := ui.NewHorizontalBox()
+	btnBox.SetPadded(true)
+	btnBox.Append(newBtn, false)
+	btnBox.Append(deleteBtn, false)
+	btnBox.Append(saveBtn, true)
+
+	box := ui.NewVerticalBox()
+	box.SetPadded(true)
+	box.Append(form, true)
+	box.Append(btnBox, false)
+	win.SetChild(box)
+	win.Show()
+}
@@ -0,0 +1,241 @@
+// Package units 精确的金额换算：十进制字符串（如 "1.5 ether"、"20 gwei"、"0.001"）
+// 与最小单位（wei 或代币的最小单位，*big.Int）之间互相转换，不经过浮点数
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Ether 原生币的小数位数
+const Ether = 18
+
+// names 原生币的单位及其小数位数
+var names = map[string]int{
+	"wei":    0,
+	"kwei":   3,
+	"mwei":   6,
+	"gwei":   9,
+	"szabo":  12,
+	"finney": 15,
+	"ether":  18,
+	"eth":    18,
+}
+
+// ParseIn 把原生币数量换算成 wei，不带单位时按 unit 换算，如 ParseIn("20", "gwei")。
+// 数量可以带单位，如 "1.5 ether"、"20 gwei"。不允许负数，小数位数超过精度时返回错误，不会舍入。
+func ParseIn(s, unit string) (*big.Int, error) {
+	num, suffix := splitUnit(strings.TrimSpace(s))
+	if suffix != "" {
+		unit = suffix
+	}
+	decimals, ok := names[strings.ToLower(unit)]
+	if !ok {
+		return nil, fmt.Errorf("未知的单位：%s", unit)
+	}
+	a, err := ParseAmount(num)
+	if err != nil {
+		return nil, err
+	}
+	return a.Raw(decimals)
+}
+
+// ParseEther 把 ETH 数量换算成 wei，可以带单位
+func ParseEther(s string) (*big.Int, error) {
+	return ParseIn(s, "ether")
+}
+
+// MustParseEther 与 ParseEther 相同，出错时 panic，只用于常量
+func MustParseEther(s string) *big.Int {
+	v, err := ParseEther(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// ParseToken 按代币的 decimals 位小数把数量换算成最小单位，如 ParseToken("0.001", 6) 为 1000。
+// 原生币的单位对代币没有意义，带单位时返回错误，以免 "1 ether" 按 18 位小数换算。
+func ParseToken(s string, decimals int) (*big.Int, error) {
+	num, unit := splitUnit(strings.TrimSpace(s))
+	if unit != "" {
+		return nil, fmt.Errorf("代币数量不能带单位：%s", s)
+	}
+	a, err := ParseAmount(num)
+	if err != nil {
+		return nil, err
+	}
+	return a.Raw(decimals)
+}
+
+// Format 把最小单位按 decimals 位小数格式化，去掉小数末尾的 0，如 Format(1500, 3) 为 "1.5"
+func Format(v *big.Int, decimals int) string {
+	if v == nil {
+		return "-"
+	}
+	return FromRaw(v, decimals).String()
+}
+
+// FormatEther 把 wei 格式化为 ETH
+func FormatEther(wei *big.Int) string {
+	return Format(wei, Ether)
+}
+
+// splitUnit 拆分数字与末尾的单位，"1.5ether" 与 "1.5 ether" 都可以
+func splitUnit(s string) (string, string) {
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != '-' && r != '+'
+	})
+	if i < 0 {
+		return s, ""
+	}
+	return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i:])
+}
+
+// Amount 精确的十进制数量，如 1.5 个代币。与小数位数无关，换算成最小单位时才检查精度。
+// 零值为 0。
+type Amount struct {
+	digits *big.Int // 去掉小数点后的数字
+	scale  int      // 小数位数，末尾没有多余的 0
+}
+
+// NewAmount 整数数量
+func NewAmount(n int64) Amount {
+	return Amount{digits: big.NewInt(n)}.norm()
+}
+
+// FromRaw 把最小单位按 decimals 位小数转换为数量，可以是负数，如余额的变化
+func FromRaw(v *big.Int, decimals int) Amount {
+	return Amount{digits: new(big.Int).Set(v), scale: decimals}.norm()
+}
+
+// ParseAmount 解析不带单位的十进制数量，如 "100"、"0.5"、".25"，不允许负数与科学计数法
+func ParseAmount(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i+1:]
+	}
+	if intPart == "" && frac == "" {
+		return Amount{}, fmt.Errorf("数量格式错误：%q", s)
+	}
+	for _, part := range []string{intPart, frac} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				if r == '-' {
+					return Amount{}, fmt.Errorf("数量不能为负数：%s", s)
+				}
+				return Amount{}, fmt.Errorf("数量格式错误：%s", s)
+			}
+		}
+	}
+	digits, _ := new(big.Int).SetString("0"+intPart+frac, 10)
+	return Amount{digits: digits, scale: len(frac)}.norm(), nil
+}
+
+// norm 去掉小数末尾的 0
+func (a Amount) norm() Amount {
+	if a.digits == nil || a.digits.Sign() == 0 {
+		return Amount{}
+	}
+	ten := big.NewInt(10)
+	d := new(big.Int).Set(a.digits)
+	m := new(big.Int)
+	for a.scale > 0 {
+		q, r := new(big.Int).QuoRem(d, ten, m)
+		if r.Sign() != 0 {
+			break
+		}
+		d = q
+		a.scale--
+	}
+	a.digits = d
+	return a
+}
+
+func (a Amount) int() *big.Int {
+	if a.digits == nil {
+		return new(big.Int)
+	}
+	return a.digits
+}
+
+// Sign 数量为负数时返回 -1，为 0 时返回 0，为正数时返回 1
+func (a Amount) Sign() int {
+	return a.int().Sign()
+}
+
+// Add 两个数量之和
+func (a Amount) Add(b Amount) Amount {
+	x, y := a.int(), b.int()
+	scale := a.scale
+	if b.scale > scale {
+		scale = b.scale
+	}
+	x = new(big.Int).Mul(x, pow10(scale-a.scale))
+	y = new(big.Int).Mul(y, pow10(scale-b.scale))
+	return Amount{digits: x.Add(x, y), scale: scale}.norm()
+}
+
+// Cmp 比较两个数量，a < b 时返回 -1，相等时返回 0，a > b 时返回 1
+func (a Amount) Cmp(b Amount) int {
+	scale := a.scale
+	if b.scale > scale {
+		scale = b.scale
+	}
+	x := new(big.Int).Mul(a.int(), pow10(scale-a.scale))
+	y := new(big.Int).Mul(b.int(), pow10(scale-b.scale))
+	return x.Cmp(y)
+}
+
+// Raw 按 decimals 位小数换算成最小单位，小数位数超过 decimals 时返回错误
+func (a Amount) Raw(decimals int) (*big.Int, error) {
+	if decimals < 0 {
+		return nil, errors.New("小数位数不能为负数")
+	}
+	if a.scale > decimals {
+		return nil, fmt.Errorf("数量 %s 的小数位数超过了精度（%d 位）", a, decimals)
+	}
+	return new(big.Int).Mul(a.int(), pow10(decimals-a.scale)), nil
+}
+
+func (a Amount) String() string {
+	sign := ""
+	if a.Sign() < 0 {
+		sign = "-"
+	}
+	s := new(big.Int).Abs(a.int()).String()
+	if a.scale == 0 {
+		return sign + s
+	}
+	if len(s) <= a.scale {
+		s = strings.Repeat("0", a.scale-len(s)+1) + s
+	}
+	return sign + s[:len(s)-a.scale] + "." + s[len(s)-a.scale:]
+}
+
+// MarshalJSON 输出为 JSON 数字，保持精确
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON 接受 JSON 数字或字符串，如 100、1.5、"1.5"
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" || s == "" {
+		*a = Amount{}
+		return nil
+	}
+	v, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,125 @@
+package units
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseEther(t *testing.T) {
+	for s, want := range map[string]string{
+		"1.5":          "1500000000000000000",
+		"1.5 ether":    "1500000000000000000",
+		"20 gwei":      "20000000000",
+		"20gwei":       "20000000000",
+		"0.000000001":  "1000000000",
+		"1 wei":        "1",
+		".25":          "250000000000000000",
+		"3 finney":     "3000000000000000",
+		"100000000000": "100000000000000000000000000000",
+	} {
+		v, err := ParseEther(s)
+		if err != nil {
+			t.Errorf("ParseEther(%q)：%s", s, err)
+			continue
+		}
+		if v.String() != want {
+			t.Errorf("ParseEther(%q) = %s，预期 %s", s, v, want)
+		}
+	}
+	for _, s := range []string{"", "-1", "1e18", "0.5 wei", "1 btc", "1.2.3"} {
+		if v, err := ParseEther(s); err == nil {
+			t.Errorf("ParseEther(%q) = %s，预期返回错误", s, v)
+		}
+	}
+}
+
+func TestParseIn(t *testing.T) {
+	v, err := ParseIn("1.5", "gwei")
+	if err != nil || v.String() != "1500000000" {
+		t.Fatalf("ParseIn(1.5, gwei) = %v, %v", v, err)
+	}
+	v, err = ParseIn("2 gwei", "wei")
+	if err != nil || v.String() != "2000000000" {
+		t.Fatalf("ParseIn(2 gwei, wei) = %v, %v", v, err)
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	v, err := ParseToken("0.001", 6)
+	if err != nil || v.String() != "1000" {
+		t.Fatalf("ParseToken(0.001, 6) = %v, %v", v, err)
+	}
+	// 原生币的单位不能改变代币的小数位数
+	for _, s := range []string{"1 ether", "1ether", "20 gwei", "5 wei"} {
+		if v, err := ParseToken(s, 6); err == nil {
+			t.Errorf("ParseToken(%q, 6) = %s，预期返回错误", s, v)
+		}
+	}
+	if _, err := ParseToken("0.0001", 3); err == nil {
+		t.Error("小数位数超过精度时应当返回错误")
+	}
+}
+
+func TestAmountString(t *testing.T) {
+	for _, c := range []struct {
+		raw      int64
+		decimals int
+		want     string
+	}{
+		{0, 18, "0"},
+		{1500, 3, "1.5"},
+		{5, 3, "0.005"},
+		{100, 2, "1"},
+		{-1500, 3, "-1.5"},
+		{-5, 3, "-0.005"},
+		{-7, 0, "-7"},
+	} {
+		if got := Format(big.NewInt(c.raw), c.decimals); got != c.want {
+			t.Errorf("Format(%d, %d) = %s，预期 %s", c.raw, c.decimals, got, c.want)
+		}
+	}
+	if got := Format(nil, 18); got != "-" {
+		t.Errorf("Format(nil) = %s", got)
+	}
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	a, _ := ParseAmount("1.75")
+	b, _ := ParseAmount("0.25")
+	sum := a.Add(b)
+	if sum.String() != "2" || sum.Cmp(NewAmount(2)) != 0 {
+		t.Fatalf("1.75 + 0.25 = %s", sum)
+	}
+	if a.Cmp(b) != 1 || b.Cmp(a) != -1 {
+		t.Fatal("Cmp 结果错误")
+	}
+	if FromRaw(big.NewInt(-1), 0).Sign() != -1 {
+		t.Fatal("负数的 Sign 应当为 -1")
+	}
+	raw, err := a.Raw(2)
+	if err != nil || raw.String() != "175" {
+		t.Fatalf("1.75 按 2 位小数 = %v, %v", raw, err)
+	}
+	if _, err := a.Raw(1); err == nil {
+		t.Fatal("小数位数超过精度时应当返回错误")
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	var a Amount
+	for s, want := range map[string]string{`1.5`: "1.5", `"0.25"`: "0.25", `null`: "0", `""`: "0", `100`: "100"} {
+		if err := a.UnmarshalJSON([]byte(s)); err != nil {
+			t.Fatalf("UnmarshalJSON(%s)：%s", s, err)
+		}
+		if a.String() != want {
+			t.Errorf("UnmarshalJSON(%s) = %s，预期 %s", s, a, want)
+		}
+		data, _ := a.MarshalJSON()
+		if string(data) != want {
+			t.Errorf("MarshalJSON = %s，预期 %s", data, want)
+		}
+	}
+	if err := a.UnmarshalJSON([]byte(`"-1"`)); err == nil {
+		t.Error("负数应当返回错误")
+	}
+}